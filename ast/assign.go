@@ -7,16 +7,24 @@ import (
 )
 
 type Assign struct {
-	Token token.Token
-	Name  Expression
-	Value Expression
+	Token    token.Token
+	Name     Expression
+	Operator string // = or a compound operator like +=
+	Value    Expression
 }
 
 func (as *Assign) TokenLiteral() string { return as.Token.Literal }
 func (as *Assign) String() string {
 	var out bytes.Buffer
 	out.WriteString(as.Name.String())
-	out.WriteString(" = ")
+	out.WriteString(" " + as.operator() + " ")
 	out.WriteString(as.Value.String())
 	return out.String()
 }
+
+func (as *Assign) operator() string {
+	if as.Operator == "" {
+		return "="
+	}
+	return as.Operator
+}
@@ -29,6 +29,9 @@ type Expression interface {
 
 type Program struct {
 	Statements []Statement
+
+	// Locals are the variables of the program in the order of their slots
+	Locals *Locals
 }
 
 func (p *Program) TokenLiteral() string {
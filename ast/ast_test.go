@@ -14,7 +14,15 @@ func TestString(t *testing.T) {
 	}{
 		{
 			`a = "test"`,
-			"a = test",
+			`a = "test"`,
+		},
+		{
+			`a += 1`,
+			"a += 1",
+		},
+		{
+			`a[0] -= 2`,
+			"(a[0]) -= 2",
 		},
 		{
 			`a = [1,2,3, true]`,
@@ -40,6 +48,10 @@ func TestString(t *testing.T) {
 			"while (true)\n  puts(true)\nend",
 			"while (true)\n  puts(true)\nend",
 		},
+		{
+			"def () { yield 1 }",
+			"def() yield (1)",
+		},
 		{
 			"while (true) {\n  puts(true)\n}",
 			"while (true)\n  puts(true)\nend",
@@ -48,11 +60,123 @@ func TestString(t *testing.T) {
 			"while {\n  puts(true)\n}",
 			"",
 		},
+		{
+			"if (a)\n  puts(1)\nelsif (b)\n  puts(2)\nelsif (c)\n  puts(3)\nelse\n  puts(4)\nend",
+			"if (a)\n  puts(1)\nelsif (b)\n  puts(2)\nelsif (c)\n  puts(3)\nelse\n  puts(4)\nend",
+		},
+		{
+			"if (a) { puts(1) } else if (b) { puts(2) }",
+			"if (a)\n  puts(1)\nelsif (b)\n  puts(2)\nend",
+		},
+		{
+			"unless (a)\n  puts(1)\nelse\n  puts(2)\nend",
+			"unless (a)\n  puts(1)\nelse\n  puts(2)\nend",
+		},
+		{
+			"until (a)\n  puts(1)\nend",
+			"until (a)\n  puts(1)\nend",
+		},
+		{
+			"loop\n  puts(1)\nend",
+			"loop\n  puts(1)\nend",
+		},
+		{
+			"loop { puts(1) }",
+			"loop\n  puts(1)\nend",
+		},
+		{
+			"puts(x) if (debug)",
+			"puts(x) if (debug)",
+		},
+		{
+			"i = i + 1 while i < 10",
+			"i = (i + 1) while ((i < 10))",
+		},
+		{
+			"return (1) unless (a)",
+			"return (1) unless (a)",
+		},
+		{
+			"a until (b)",
+			"a until (b)",
+		},
+		{
+			"a = nil",
+			"a = null",
+		},
+		{
+			"a.b.c(1).d",
+			"a.b.c(1).d",
+		},
+		{
+			"a.b += 1",
+			"a.b += 1",
+		},
+		{
+			"f(*a, 1)",
+			"f(*a, 1)",
+		},
+		{
+			`{**a, "b": 1, c}`,
+			`{**a, "b":1, c}`,
+		},
+		{
+			`{c: 1, "d": d, e: e}`,
+			`{c:1, "d":d, e}`,
+		},
+		{
+			"a&.b()&.c(1)",
+			"a&.b()&.c(1)",
+		},
+		{
+			"a ?? b == c",
+			"(a ?? (b == c))",
+		},
+		{
+			"a ?? b || c",
+			"(a ?? (b || c))",
+		},
+		{
+			"->(x) { x * 2 }",
+			"->(x) (x * 2)",
+		},
+		{
+			"f(|a, b| a + b)",
+			"f(|a, b| (a + b))",
+		},
+		{
+			"f(1) { |x| x }",
+			"f(1) { |x| x }",
+		},
+		{
+			"a.each do |x| puts(x) end",
+			"a.each() do |x| puts(x) end",
+		},
+		{
+			"f() { 1 }",
+			"f() { 1 }",
+		},
+		{
+			"a + 1 |> f(b) |> g == c",
+			"((((a + 1) |> f(b)) |> g) == c)",
+		},
+		{
+			"a |> &:upcase >> &:reverse",
+			"(a |> (&:upcase >> &:reverse))",
+		},
+		{
+			"[x * 2 foreach x in xs if x > 1]",
+			"[(x * 2) foreach x in xs if (x > 1)]",
+		},
+		{
+			"{k: v foreach k, v in h foreach [a, b] in v}",
+			"{k: v foreach k, v in h foreach [a, b] in v}",
+		},
 	}
 
 	for _, tt := range tests {
 		l := lexer.New(tt.input)
-		p := parser.New(l, make(map[string]struct{}))
+		p := parser.New(l, nil)
 
 		program, _ := p.ParseProgram()
 
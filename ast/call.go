@@ -11,14 +11,23 @@ type Call struct {
 	Token     token.Token // The ( token
 	Callable  Expression
 	Arguments []Expression
+
+	// Tail is true if the function returns the result of the call directly
+	Tail bool
 }
 
 func (ce *Call) TokenLiteral() string { return ce.Token.Literal }
 func (ce *Call) String() string {
 	var out bytes.Buffer
 
+	arguments := ce.Arguments
+	block := ce.Block()
+	if block != nil {
+		arguments = arguments[:len(arguments)-1]
+	}
+
 	args := []string{}
-	for _, a := range ce.Arguments {
+	for _, a := range arguments {
 		args = append(args, a.String())
 	}
 
@@ -27,5 +36,22 @@ func (ce *Call) String() string {
 	out.WriteString(strings.Join(args, ", "))
 	out.WriteString(")")
 
+	if block != nil {
+		out.WriteString(" ")
+		out.WriteString(block.String())
+	}
+
 	return out.String()
 }
+
+// Block returns the trailing block of the call or nil if there is none.
+func (ce *Call) Block() *Function {
+	if len(ce.Arguments) == 0 {
+		return nil
+	}
+
+	if fn, ok := ce.Arguments[len(ce.Arguments)-1].(*Function); ok && fn.Block {
+		return fn
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/flipez/rocket-lang/token"
+)
+
+// Comprehension builds an array like `[x * 2 foreach x in xs if x > 1]` or a
+// hash like `{k: v foreach k, v in h}`. Key is nil for an array.
+type Comprehension struct {
+	Token   token.Token // the [ or { token
+	Key     Expression
+	Value   Expression
+	Clauses []ComprehensionClause
+}
+
+// ComprehensionClause is a foreach without a body which is optionally
+// followed by a condition filtering its values.
+type ComprehensionClause struct {
+	Foreach   *Foreach
+	Condition Expression
+
+	// Locals are the variables of the clause in the order of their slots
+	Locals *Locals
+}
+
+func (c *Comprehension) TokenLiteral() string { return c.Token.Literal }
+func (c *Comprehension) String() string {
+	var out bytes.Buffer
+
+	if c.Key != nil {
+		out.WriteString("{")
+		out.WriteString(c.Key.String())
+		out.WriteString(": ")
+	} else {
+		out.WriteString("[")
+	}
+	out.WriteString(c.Value.String())
+
+	for _, clause := range c.Clauses {
+		out.WriteString(" ")
+		out.WriteString(clause.Foreach.Header())
+		if clause.Condition != nil {
+			out.WriteString(" if ")
+			out.WriteString(clause.Condition.String())
+		}
+	}
+
+	if c.Key != nil {
+		out.WriteString("}")
+	} else {
+		out.WriteString("]")
+	}
+
+	return out.String()
+}
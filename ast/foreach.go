@@ -10,21 +10,39 @@ type Foreach struct {
 	Token token.Token
 	Index string
 	Ident string
-	Value Expression
-	Body  *Block
+	// Pattern replaces Ident if the values are destructured
+	Pattern Expression
+	Value   Expression
+	Body    *Block
+
+	// Locals are the variables of the loop in the order of their slots
+	Locals *Locals
 }
 
 func (fes *Foreach) TokenLiteral() string { return fes.Token.Literal }
 func (fes *Foreach) String() string {
 	var out bytes.Buffer
-	out.WriteString("foreach ")
-	out.WriteString(fes.Index)
-	out.WriteString(", ")
-	out.WriteString(fes.Ident)
-	out.WriteString(" in ")
-	out.WriteString(fes.Value.String())
+	out.WriteString(fes.Header())
 	out.WriteString(" {\n  ")
 	out.WriteString(fes.Body.String())
 	out.WriteString("\n}")
 	return out.String()
 }
+
+// Header returns the foreach without its body, like `foreach i, x in xs`.
+func (fes *Foreach) Header() string {
+	var out bytes.Buffer
+	out.WriteString("foreach ")
+	if fes.Index != "" {
+		out.WriteString(fes.Index)
+		out.WriteString(", ")
+	}
+	if fes.Pattern != nil {
+		out.WriteString(fes.Pattern.String())
+	} else {
+		out.WriteString(fes.Ident)
+	}
+	out.WriteString(" in ")
+	out.WriteString(fes.Value.String())
+	return out.String()
+}
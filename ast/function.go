@@ -12,6 +12,17 @@ type Function struct {
 	Name       string
 	Parameters []*Identifier
 	Body       *Block
+
+	// Generator is true if the body contains a yield
+	Generator bool
+
+	// Block is true for a trailing block like `f() { |x| x }` which is
+	// passed as the last argument of a call
+	Block bool
+
+	// Locals are the variables of the function, starting with its
+	// parameters, in the order of their slots
+	Locals *Locals
 }
 
 func (fl *Function) TokenLiteral() string { return fl.Token.Literal }
@@ -23,11 +34,30 @@ func (fl *Function) String() string {
 		params = append(params, p.String())
 	}
 
-	out.WriteString(fl.TokenLiteral())
-	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") ")
-	out.WriteString(fl.Body.String())
+	blockParams := ""
+	if len(params) > 0 {
+		blockParams = "|" + strings.Join(params, ", ") + "| "
+	}
+
+	switch {
+	case fl.Block && fl.Token.Type == token.DO:
+		out.WriteString("do " + blockParams)
+		out.WriteString(fl.Body.String())
+		out.WriteString(" end")
+	case fl.Block:
+		out.WriteString("{ " + blockParams)
+		out.WriteString(fl.Body.String())
+		out.WriteString(" }")
+	case fl.Token.Type == token.BIT_OR || fl.Token.Type == token.OR:
+		out.WriteString("|" + strings.Join(params, ", ") + "| ")
+		out.WriteString(fl.Body.String())
+	default:
+		out.WriteString(fl.TokenLiteral())
+		out.WriteString("(")
+		out.WriteString(strings.Join(params, ", "))
+		out.WriteString(") ")
+		out.WriteString(fl.Body.String())
+	}
 
 	return out.String()
 }
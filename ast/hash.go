@@ -9,7 +9,14 @@ import (
 
 type Hash struct {
 	Token token.Token
-	Pairs map[Expression]Expression
+	Pairs []HashPair
+}
+
+// HashPair is a key and value of a hash literal. A spread like `**other`
+// is stored as a pair with a Splat key and no value.
+type HashPair struct {
+	Key   Expression
+	Value Expression
 }
 
 func (hl *Hash) TokenLiteral() string { return hl.Token.Literal }
@@ -17,8 +24,19 @@ func (hl *Hash) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, pair := range hl.Pairs {
+		if pair.Value == nil {
+			pairs = append(pairs, pair.Key.String())
+			continue
+		}
+		// `{name}` is short for `{name: name}`
+		if symbol, ok := pair.Key.(*Symbol); ok {
+			if ident, ok := pair.Value.(*Identifier); ok && ident.Value == symbol.Value {
+				pairs = append(pairs, ident.Value)
+				continue
+			}
+		}
+		pairs = append(pairs, pair.Key.String()+":"+pair.Value.String())
 	}
 
 	out.WriteString("{")
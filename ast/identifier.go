@@ -7,6 +7,16 @@ import (
 type Identifier struct {
 	Token token.Token // the token.IDENT token
 	Value string
+
+	// Resolved is set by the resolver of the parser. Depth is the number of
+	// scopes between the identifier and the scope of its variable, Slot is
+	// the index of the variable in that scope. The Slot of names which are
+	// not declared, like builtins, is -1. Names which are not resolved are
+	// looked up by name, like a variable of an outer scope a function
+	// assigns to which is only bound after the function.
+	Resolved bool
+	Depth    int
+	Slot     int
 }
 
 func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
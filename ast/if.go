@@ -6,8 +6,10 @@ import (
 	"github.com/flipez/rocket-lang/token"
 )
 
+// If is an if expression. An elsif is stored as an Alternative holding
+// nothing but an If with an ELSIF token.
 type If struct {
-	Token       token.Token // the if token
+	Token       token.Token // the if or elsif token
 	Condition   Expression
 	Consequence *Block
 	Alternative *Block
@@ -17,16 +19,42 @@ func (ie *If) TokenLiteral() string { return ie.Token.Literal }
 func (ie *If) String() string {
 	var out bytes.Buffer
 
-	out.WriteString("if (")
+	out.WriteString("if ")
+	ie.writeBranches(&out)
+	out.WriteString("\nend")
+
+	return out.String()
+}
+
+func (ie *If) writeBranches(out *bytes.Buffer) {
+	out.WriteString("(")
 	out.WriteString(ie.Condition.String())
 	out.WriteString(")\n  ")
 	out.WriteString(ie.Consequence.String())
 
-	if ie.Alternative != nil {
+	if elsif := ie.Elsif(); elsif != nil {
+		out.WriteString("\nelsif ")
+		elsif.writeBranches(out)
+	} else if ie.Alternative != nil {
 		out.WriteString("\nelse\n  ")
 		out.WriteString(ie.Alternative.String())
 	}
-	out.WriteString("\nend")
+}
 
-	return out.String()
+// Elsif returns the If of the following elsif branch, if there is one.
+func (ie *If) Elsif() *If {
+	if ie.Alternative == nil || len(ie.Alternative.Statements) != 1 {
+		return nil
+	}
+
+	stmt, ok := ie.Alternative.Statements[0].(*ExpressionStatement)
+	if !ok {
+		return nil
+	}
+
+	elsif, ok := stmt.Expression.(*If)
+	if !ok || elsif.Token.Type != token.ELSIF {
+		return nil
+	}
+	return elsif
 }
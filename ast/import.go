@@ -2,7 +2,6 @@ package ast
 
 import (
 	"bytes"
-	"fmt"
 
 	"github.com/flipez/rocket-lang/token"
 )
@@ -18,7 +17,7 @@ func (ie *Import) String() string {
 
 	out.WriteString(ie.TokenLiteral())
 	out.WriteString("(")
-	out.WriteString(fmt.Sprintf("\"%s\"", ie.Name))
+	out.WriteString(ie.Name.String())
 	out.WriteString(")")
 
 	return out.String()
@@ -0,0 +1,36 @@
+package ast
+
+// Locals are the variables the resolver found in a scope in the order of
+// their slots. Every environment of the scope has a slot for each of them.
+type Locals struct {
+	Names []string
+	slots map[string]int
+}
+
+func NewLocals(names []string) *Locals {
+	slots := make(map[string]int, len(names))
+	for i, name := range names {
+		slots[name] = i
+	}
+	return &Locals{Names: names, slots: slots}
+}
+
+// Slot returns the slot of name, or -1 if it is no local. The locals of a
+// scope the resolver didn't see are nil.
+func (l *Locals) Slot(name string) int {
+	if l == nil {
+		return -1
+	}
+	if slot, ok := l.slots[name]; ok {
+		return slot
+	}
+	return -1
+}
+
+// Len returns the amount of locals.
+func (l *Locals) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.Names)
+}
@@ -0,0 +1,20 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/flipez/rocket-lang/token"
+)
+
+type Loop struct {
+	Token token.Token
+	Body  *Block
+
+	// Locals are the variables of the loop in the order of their slots
+	Locals *Locals
+}
+
+func (l *Loop) TokenLiteral() string { return l.Token.Literal }
+func (l *Loop) String() string {
+	return fmt.Sprintf("%s\n  %s\nend", l.TokenLiteral(), l.Body)
+}
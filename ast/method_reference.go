@@ -0,0 +1,15 @@
+package ast
+
+import (
+	"github.com/flipez/rocket-lang/token"
+)
+
+// MethodReference is a function calling a method on its argument, like
+// `&:upcase`
+type MethodReference struct {
+	Token token.Token // the &: token
+	Name  string
+}
+
+func (mr *MethodReference) TokenLiteral() string { return mr.Token.Literal }
+func (mr *MethodReference) String() string       { return mr.Token.Literal + mr.Name }
@@ -0,0 +1,24 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/flipez/rocket-lang/token"
+)
+
+// Modifier is a statement followed by if, unless, while or until, like
+// `puts(x) if debug`.
+type Modifier struct {
+	Token     token.Token // the if, unless, while or until token
+	Statement Statement
+	Condition Expression
+
+	// Locals are the variables of a while or until loop in the order of
+	// their slots
+	Locals *Locals
+}
+
+func (m *Modifier) TokenLiteral() string { return m.Token.Literal }
+func (m *Modifier) String() string {
+	return fmt.Sprintf("%s %s (%s)", m.Statement, m.TokenLiteral(), m.Condition)
+}
@@ -0,0 +1,12 @@
+package ast
+
+import (
+	"github.com/flipez/rocket-lang/token"
+)
+
+type Null struct {
+	Token token.Token
+}
+
+func (n *Null) TokenLiteral() string { return n.Token.Literal }
+func (n *Null) String() string       { return "null" }
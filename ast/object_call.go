@@ -10,13 +10,20 @@ type ObjectCall struct {
 	Token  token.Token
 	Object Expression
 	Call   Expression
+	// Safe is set for `&.`, which returns null instead of calling a method
+	// on null
+	Safe bool
 }
 
 func (oce *ObjectCall) TokenLiteral() string { return oce.Token.Literal }
 func (oce *ObjectCall) String() string {
 	var out bytes.Buffer
 	out.WriteString(oce.Object.String())
-	out.WriteString(".")
+	if oce.Safe {
+		out.WriteString("&.")
+	} else {
+		out.WriteString(".")
+	}
 	out.WriteString(oce.Call.String())
 
 	return out.String()
@@ -0,0 +1,16 @@
+package ast
+
+import (
+	"github.com/flipez/rocket-lang/token"
+)
+
+// Splat is `*value`. In a destructuring pattern it collects the remaining
+// elements of an array, in calls and array literals it spreads the values
+// of value. In hash literals `**value` spreads the pairs of a hash.
+type Splat struct {
+	Token token.Token // the * or ** token
+	Value Expression
+}
+
+func (s *Splat) TokenLiteral() string { return s.Token.Literal }
+func (s *Splat) String() string       { return s.TokenLiteral() + s.Value.String() }
@@ -1,13 +1,28 @@
 package ast
 
 import (
+	"strings"
+
 	"github.com/flipez/rocket-lang/token"
 )
 
 type String struct {
 	Token token.Token
 	Value string
+
+	// Delimiter is the opening delimiter of the string: ", """, ' or the
+	// marker of a heredoc like <<~EOS. The token literal keeps the string
+	// as written in the source.
+	Delimiter string
 }
 
 func (sl *String) TokenLiteral() string { return sl.Token.Literal }
 func (sl *String) String() string       { return sl.TokenLiteral() }
+
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "\x00", `\0`)
+
+// Quote returns value as a string in double quotes, with the characters
+// which need it escaped.
+func Quote(value string) string {
+	return `"` + quoteReplacer.Replace(value) + `"`
+}
@@ -0,0 +1,15 @@
+package ast
+
+import (
+	"github.com/flipez/rocket-lang/token"
+)
+
+// Symbol is a symbol literal like :name, or the key of a hash written as
+// {name: 1}.
+type Symbol struct {
+	Token token.Token
+	Value string
+}
+
+func (s *Symbol) TokenLiteral() string { return s.Token.Literal }
+func (s *Symbol) String() string       { return s.TokenLiteral() }
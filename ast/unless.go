@@ -0,0 +1,32 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/flipez/rocket-lang/token"
+)
+
+type Unless struct {
+	Token       token.Token // the unless token
+	Condition   Expression
+	Consequence *Block
+	Alternative *Block
+}
+
+func (u *Unless) TokenLiteral() string { return u.Token.Literal }
+func (u *Unless) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("unless (")
+	out.WriteString(u.Condition.String())
+	out.WriteString(")\n  ")
+	out.WriteString(u.Consequence.String())
+
+	if u.Alternative != nil {
+		out.WriteString("\nelse\n  ")
+		out.WriteString(u.Alternative.String())
+	}
+	out.WriteString("\nend")
+
+	return out.String()
+}
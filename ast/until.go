@@ -0,0 +1,21 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/flipez/rocket-lang/token"
+)
+
+type Until struct {
+	Token     token.Token
+	Condition Expression
+	Body      *Block
+
+	// Locals are the variables of the loop in the order of their slots
+	Locals *Locals
+}
+
+func (u *Until) TokenLiteral() string { return u.Token.Literal }
+func (u *Until) String() string {
+	return fmt.Sprintf("%s (%s)\n  %s\nend", u.TokenLiteral(), u.Condition, u.Body)
+}
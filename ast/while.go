@@ -10,6 +10,9 @@ type While struct {
 	Token     token.Token
 	Condition Expression
 	Body      *Block
+
+	// Locals are the variables of the loop in the order of their slots
+	Locals *Locals
 }
 
 func (w *While) TokenLiteral() string { return w.Token.Literal }
@@ -0,0 +1,26 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/flipez/rocket-lang/token"
+)
+
+type Yield struct {
+	Token token.Token
+	Value Expression
+}
+
+func (y *Yield) TokenLiteral() string { return y.Token.Literal }
+func (y *Yield) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(y.TokenLiteral())
+
+	if y.Value != nil {
+		out.WriteString(" (")
+		out.WriteString(y.Value.String())
+		out.WriteString(")")
+	}
+	return out.String()
+}
@@ -17,14 +17,23 @@ type templateData struct {
 func main() {
 	default_methods := object.ListObjectMethods()["*"]
 	string_methods := object.ListObjectMethods()[object.STRING_OBJ]
+	symbol_methods := object.ListObjectMethods()[object.SYMBOL_OBJ]
 	integer_methods := object.ListObjectMethods()[object.INTEGER_OBJ]
 	array_methods := object.ListObjectMethods()[object.ARRAY_OBJ]
 	hash_methods := object.ListObjectMethods()[object.HASH_OBJ]
+	set_methods := object.ListObjectMethods()[object.SET_OBJ]
 	boolean_methods := object.ListObjectMethods()[object.BOOLEAN_OBJ]
 	error_methods := object.ListObjectMethods()[object.ERROR_OBJ]
 	file_methods := object.ListObjectMethods()[object.FILE_OBJ]
 	null_methods := object.ListObjectMethods()[object.NULL_OBJ]
 	float_methods := object.ListObjectMethods()[object.FLOAT_OBJ]
+	regex_methods := object.ListObjectMethods()[object.REGEX_OBJ]
+	process_methods := object.ListObjectMethods()[object.PROCESS_OBJ]
+	iterator_methods := object.ListObjectMethods()[object.ITERATOR_OBJ]
+	heap_methods := object.ListObjectMethods()[object.HEAP_OBJ]
+	deque_methods := object.ListObjectMethods()[object.DEQUE_OBJ]
+	counter_methods := object.ListObjectMethods()[object.COUNTER_OBJ]
+	default_hash_methods := object.ListObjectMethods()[object.DEFAULT_HASH_OBJ]
 
 	tempData := templateData{
 		Title: "String",
@@ -49,18 +58,52 @@ s[-2] = "E"
 puts(s)
 
 // should output
-"c"
-"e"
-"ab"
-"abcd"
-"cdef"
-"ef"
-"bcd"
-"abCdEf"`,
+c
+e
+ab
+abcd
+cdef
+ef
+bcd
+abCdEf
+
+query = <<~SQL
+  SELECT *
+    FROM users
+SQL
+json = """
+{"name": "rocket"}
+"""
+path = 'C:\new\dir'`,
+		Description: "Strings in double quotes can contain the escape sequences `\\n`, `\\r`, `\\t`, `\\0`, `\\\\` and `\\\"`, other backslashes are kept as they are. Strings in single quotes are raw, backslashes have no special meaning in them.\n\n" +
+			"Strings in triple quotes (`\"\"\"`) can contain double quotes, a newline right after the opening quotes is not part of the string. Heredocs start with a marker like `<<~SQL` and their text starts on the next line. It ends with a line containing only the name of the marker, the indentation all lines have in common is removed.\n\n" +
+			"All strings can span several lines.",
 		LiteralMethods: string_methods,
 		DefaultMethods: default_methods}
 	create_doc("docs/templates/literal.md", "docs/content/docs/literals/string.md", tempData)
 
+	tempData = templateData{
+		Title:       "Symbol",
+		Description: "A symbol is a name written with a leading colon, like `:name`. There is only one symbol of every name, which makes comparing them and using them as hash keys cheap. Symbols are mostly used as hash keys and where a fixed set of names is expected.\n\nA colon directly after a value, like in `s[1:n]`, is no symbol, and neither is one opening an index or following the condition of a ternary: `s[:n]` and `c ? 1 :n` use the variable `n`. To look up a symbol key, put it in parentheses like `h[(:name)]`, or use `h.name`.",
+		Example: `status = :ok
+puts(status == :ok)
+puts(status)
+
+user = {name: "Anna", role: :admin}
+puts(user[(:name)])
+puts(user.role)
+puts("role".to_sym() == :role)
+
+// should output
+true
+ok
+Anna
+admin
+true`,
+		LiteralMethods: symbol_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/symbol.md", tempData)
+
 	tempData = templateData{
 		Title: "Array",
 		Example: `a = [1, 2, 3, 4, 5]
@@ -85,7 +128,8 @@ puts(a[1:-2])
 	create_doc("docs/templates/literal.md", "docs/content/docs/literals/array.md", tempData)
 
 	tempData = templateData{
-		Title: "Hash",
+		Title:       "Hash",
+		Description: "Keys can be strings, symbols, integers, floats, booleans, arrays, hashes and sets. Two keys are the same if they are equal (`==`). Strings, arrays, hashes and sets are copied when used as key, changing them afterwards does not affect the hash.\n\nA name followed by a colon is a symbol key, `{name: 1}` and `{name : 1}` are short for `{:name: 1}`. To use the value of a variable as key, put it in parentheses: `{(name): 1}`. `{name}` is short for `{name: name}`.\n\nString and symbol keys can also be read and written with a dot (`h.name`), unless the hash has a method with that name. The string key is used if the hash has both. See [Dot Access](/docs/specification/modules/#dot-access).",
 		Example: `people = [{"name": "Anna", "age": 24}, {"name": "Bob", "age": 99}];
 
 // reassign of values
@@ -106,12 +150,41 @@ h["b"] = "moo"
 1
 true
 3
-"moo"
+moo
 true`,
 		LiteralMethods: hash_methods,
 		DefaultMethods: default_methods}
 	create_doc("docs/templates/literal.md", "docs/content/docs/literals/hash.md", tempData)
 
+	tempData = templateData{
+		Title:       "Set",
+		Description: "A set holds distinct values in the order they were added. Sets are created with `set()`, which takes an optional array or other iterable. Elements can be of every type usable as hash key, like hash keys mutable elements are copied when added.\n\nThere is no literal for sets, `{a, b}` is already a hash with the keys `a` and `b`.\n\nThe operators `|`, `&` and `-` return the union, intersection and difference of two sets.",
+		Example: `a = set([1, 2, 2, 3])
+b = set([3, 4])
+
+puts(a)
+puts(a.include?(2))
+puts(a | b)
+puts(a & b)
+puts(a - b)
+
+foreach element in a {
+  puts(element)
+}
+
+// should output
+set([1, 2, 3])
+true
+set([1, 2, 3, 4])
+set([3])
+set([1, 2])
+1
+2
+3`,
+		LiteralMethods: set_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/set.md", tempData)
+
 	tempData = templateData{
 		Title:       "Boolean",
 		Description: "A Boolean can represent two values: `true` and `false` and can be used in control flows.",
@@ -140,7 +213,17 @@ is_true = a != b;`,
 	create_doc("docs/templates/literal.md", "docs/content/docs/literals/file.md", tempData)
 
 	tempData = templateData{
-		Title:          "Null",
+		Title:       "Null",
+		Description: "`null` (or `nil`) is the absence of a value. It is returned by missing hash keys, out of range indexes and functions without a return value. Only `null` is equal to `null`.",
+		Example: `a = {"name": "Anna"}
+puts(a["age"] == null)
+puts(a["age"] ?? 0)
+puts(a["age"]&.plz_s())
+
+// should output
+true
+0
+null`,
 		LiteralMethods: null_methods,
 		DefaultMethods: default_methods}
 	create_doc("docs/templates/literal.md", "docs/content/docs/literals/null.md", tempData)
@@ -152,17 +235,189 @@ is_true = a != b;`,
 b = a + 2;
 
 is_true = 1 == 1;
-is_false = 1 == 2;`,
+is_false = 1 == 2;
+
+mask = 0xFF;
+flags = 0b1010;
+mode = 0o755;
+million = 1_000_000;`,
 		Description: `An integer can be positiv or negative and is always internally represented by a 64-Bit Integer.
 
-To cast a negative integer a digit can be prefixed with a - eg. -456.`,
+To cast a negative integer a digit can be prefixed with a - eg. -456.
+
+Integers can also be written in hexadecimal, binary or octal with the prefixes ` + "`0x`, `0b` and `0o`" + `. Digits can be grouped by underscores, eg. ` + "`1_000_000`" + `.`,
 		LiteralMethods: integer_methods,
 		DefaultMethods: default_methods}
 	create_doc("docs/templates/literal.md", "docs/content/docs/literals/integer.md", tempData)
 
-	tempData = templateData{Title: "Float", LiteralMethods: float_methods, DefaultMethods: default_methods}
+	tempData = templateData{
+		Title: "Float",
+		Example: `a = 1.5;
+
+b = 1.5e-3;
+c = 2E10;
+d = 1_000.25;`,
+		Description: `A float is internally represented by a 64-Bit floating point number.
+
+Floats can have an exponent after an ` + "`e` or `E`" + `, which turns integers into floats as well. Digits can be grouped by underscores.`,
+		LiteralMethods: float_methods,
+		DefaultMethods: default_methods}
 	create_doc("docs/templates/literal.md", "docs/content/docs/literals/float.md", tempData)
 
+	tempData = templateData{
+		Title:       "Regex",
+		Description: "A Regex is created with the `regex()` builtin and uses the [RE2 syntax](https://github.com/google/re2/wiki/Syntax).",
+		Example: `r = regex("(?P<key>[a-z]+)=(?P<value>[0-9]+)")
+
+"a=1" =~ r // => 0
+"nope" =~ r // => null
+
+m = "a=1".match(r)
+puts(m[0])
+puts(m["key"])
+puts(m[2])
+
+puts("a=1 b=2".scan(r))
+puts("a=1 b=2".gsub(r, "${value}=${key}"))
+
+// should output
+a=1
+a
+1
+[["a", "1"], ["b", "2"]]
+1=a 2=b`,
+		LiteralMethods: regex_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/regex.md", tempData)
+
+	tempData = templateData{
+		Title:       "Process",
+		Description: "A Process is a running command started with the `spawn()` builtin. Its streams are files backed by pipes.",
+		Example: `p = spawn(["tr", "a-z", "A-Z"])
+p.stdin().write("rocket")
+p.stdin().close()
+puts(p.stdout().content())
+puts(p.wait())
+
+// should output
+ROCKET
+0`,
+		LiteralMethods: process_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/process.md", tempData)
+
+	tempData = templateData{
+		Title:       "Iterator",
+		Description: "An Iterator is returned by the `iter()` method of arrays, hashes, strings and integers. Every call returns a new iterator, so the same object can be iterated in nested loops. Iterators can be used in `foreach`, which continues where the iterator currently is. Generator functions return iterators as well.\n\nThe combinators like `map` or `take` are lazy, they only read values from the iterator they are called on when needed. They take over that iterator and close it once they are done.",
+		Example: `it = [1, 2, 3].iter()
+puts(it.next())
+
+foreach i in it {
+  puts(i)
+}
+puts(it.done?())
+
+// should output
+1
+2
+3
+true`,
+		LiteralMethods: iterator_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/iterator.md", tempData)
+
+	tempData = templateData{
+		Title:       "Heap",
+		Description: "A Heap is a priority queue created with `collections.heap()`. It returns the smallest value first, or the largest first if created with `collections.heap(:max)`. Values of the same priority are returned in the order they were pushed.\n\nNumbers, strings and arrays can be ordered, arrays are compared element by element. An optional function computes the priority of every value instead: `collections.heap(:min, -> (node) { node.cost })`.",
+		Example: `queue = collections.heap()
+queue.push([5, "b"]).push([2, "a"]).push([9, "c"])
+
+while (!queue.empty?())
+  puts(queue.pop())
+end
+
+longest = collections.heap(:max, -> (s) { s.size() })
+longest.push("rocket").push("go")
+puts(longest.peek())
+
+// should output
+[2, "a"]
+[5, "b"]
+[9, "c"]
+rocket`,
+		LiteralMethods: heap_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/heap.md", tempData)
+
+	tempData = templateData{
+		Title:       "Deque",
+		Description: "A Deque is a double-ended queue created with `collections.deque()`, which takes optional initial values. Values are pushed and popped at both ends in constant time. Indexing works like for arrays.",
+		Example: `d = collections.deque([2, 3])
+d.push_front(1)
+d.push(4)
+
+puts(d)
+puts(d.pop_front())
+puts(d.pop())
+puts(d[0])
+
+// should output
+deque([1, 2, 3, 4])
+1
+4
+2`,
+		LiteralMethods: deque_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/deque.md", tempData)
+
+	tempData = templateData{
+		Title:       "Counter",
+		Description: "A Counter tallies how often values occur. It is created with `collections.counter()`, which counts the values of an optional array, string or other iterable. Indexing returns the count of a value, which is 0 for values never counted, and can also set it.\n\nIterating a counter and printing it lists the most common values first.",
+		Example: `c = collections.counter(["apple", "pear", "apple"])
+c.add("plum")
+c["pear"] += 2
+
+puts(c["apple"])
+puts(c["kiwi"])
+puts(c.most_common(1))
+
+foreach fruit, count in c {
+  puts(fruit + ": " + count.to_s())
+}
+
+// should output
+2
+0
+[["pear", 3]]
+pear: 3
+apple: 2
+plum: 1`,
+		LiteralMethods: counter_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/counter.md", tempData)
+
+	tempData = templateData{
+		Title:       "Default Hash",
+		Description: "A Default Hash is created with `collections.default_hash(default)`. Reading a missing key sets it to a new default value first. A function as default is called for every missing key, other defaults are copied, so keys never share an array or hash.\n\nApart from that it behaves like a [Hash](/docs/literals/hash/) and supports all of its methods.",
+		Example: `groups = collections.default_hash(-> { [] })
+foreach word in ["ant", "bee", "ape"] {
+  groups[word[0]].yoink(word)
+}
+puts(groups)
+
+counts = collections.default_hash(0)
+counts["a"] += 1
+puts(counts["a"])
+puts(counts["b"])
+
+// should output
+default_hash({"a": ["ant", "ape"], "b": ["bee"]})
+1
+0`,
+		LiteralMethods: default_hash_methods,
+		DefaultMethods: default_methods}
+	create_doc("docs/templates/literal.md", "docs/content/docs/literals/default_hash.md", tempData)
+
 }
 
 func create_doc(path string, target string, data templateData) bool {
@@ -3,25 +3,62 @@ package evaluator
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/flipez/rocket-lang/ast"
 	"github.com/flipez/rocket-lang/object"
 )
 
-func evalAssign(a *ast.Assign, env *object.Environment) (val object.Object) {
+func evalAssign(a *ast.Assign, env *object.Environment) object.Object {
 	evaluated := Eval(a.Value, env)
 	if object.IsError(evaluated) {
 		return evaluated
 	}
 
-	switch v := a.Name.(type) {
+	return assign(a.Name, compoundOperator(a), evaluated, env)
+}
+
+// assign stores evaluated in target. For compound assignments the current
+// value of target is combined with evaluated first.
+func assign(target ast.Expression, operator string, evaluated object.Object, env *object.Environment) object.Object {
+	switch v := target.(type) {
 	case *ast.Identifier:
-		env.Set(v.String(), evaluated)
+		if operator != "" {
+			current := evalIdentifier(v, env)
+			if object.IsError(current) {
+				return current
+			}
+			evaluated = evalInfixExpression(operator, current, evaluated)
+			if object.IsError(evaluated) {
+				return evaluated
+			}
+		}
+		setIdentifier(v, evaluated, env)
 	case *ast.Index:
-		obj, _ := env.Get(v.Left.String())
+		obj := Eval(v.Left, env)
+		if object.IsError(obj) {
+			return obj
+		}
+
+		index := Eval(v.Index, env)
+		if object.IsError(index) {
+			return index
+		}
+
+		if operator != "" {
+			current := evalIndex(obj, index)
+			if object.IsError(current) {
+				return current
+			}
+			evaluated = evalInfixExpression(operator, current, evaluated)
+			if object.IsError(evaluated) {
+				return evaluated
+			}
+		}
+
 		switch o := obj.(type) {
 		case *object.Array:
-			idx, err := handleIntegerIndex(v, env)
+			idx, err := handleIntegerIndex(index)
 			if err != nil {
 				return object.NewError(err)
 			}
@@ -38,16 +75,33 @@ func evalAssign(a *ast.Assign, env *object.Environment) (val object.Object) {
 
 			o.Elements[idx] = evaluated
 		case *object.Hash:
-			obj := Eval(v.Index, env)
-			h, ok := obj.(object.Hashable)
+			h, ok := index.(object.Hashable)
 			if !ok {
 				return object.NewErrorFormat("expected index to be hashable")
 			}
 
-			key := h.HashKey()
-			o.Pairs[key] = object.HashPair{Key: obj, Value: evaluated}
+			o.Set(h, evaluated)
+		case *object.DefaultHash:
+			h, ok := index.(object.Hashable)
+			if !ok {
+				return object.NewErrorFormat("expected index to be hashable")
+			}
+
+			o.Hash.Set(h, evaluated)
+		case *object.Counter:
+			h, ok := index.(object.Hashable)
+			if !ok {
+				return object.NewErrorFormat("expected index to be hashable")
+			}
+
+			count, ok := evaluated.(*object.Integer)
+			if !ok {
+				return object.NewErrorFormat("expected INTEGER object, got %s", evaluated.Type())
+			}
+
+			o.Set(h, count.Value)
 		case *object.String:
-			idx, err := handleIntegerIndex(v, env)
+			idx, err := handleIntegerIndex(index)
 			if err != nil {
 				return object.NewError(err)
 			}
@@ -76,12 +130,50 @@ func evalAssign(a *ast.Assign, env *object.Environment) (val object.Object) {
 		default:
 			return object.NewErrorFormat("expected object to be indexable")
 		}
+	case *ast.ObjectCall:
+		// assigning to `h.key` always writes the key, even if the hash has
+		// a method with the same name
+		obj := Eval(v.Object, env)
+		if object.IsError(obj) {
+			return obj
+		}
+
+		hash, ok := obj.(*object.Hash)
+		if !ok {
+			return object.NewErrorFormat("cannot assign to `.%s` of %s", v.Call, obj.Type())
+		}
+		key := hash.MemberKey(v.Call.String())
+
+		if operator != "" {
+			current, ok := hash.Get(key)
+			if !ok {
+				current = object.NULL
+			}
+			evaluated = evalInfixExpression(operator, current, evaluated)
+			if object.IsError(evaluated) {
+				return evaluated
+			}
+		}
+
+		hash.Set(key, evaluated)
+	case *ast.Array, *ast.Hash:
+		if err := destructure(v, evaluated, env); err != nil {
+			return err
+		}
 	}
 	return evaluated
 }
 
-func handleIntegerIndex(ai *ast.Index, env *object.Environment) (int64, error) {
-	obj := Eval(ai.Index, env)
+// compoundOperator returns the infix operator of a compound assignment
+// like `a += 1`, or an empty string for a plain assignment.
+func compoundOperator(a *ast.Assign) string {
+	if a.Operator == "" || a.Operator == "=" {
+		return ""
+	}
+	return strings.TrimSuffix(a.Operator, "=")
+}
+
+func handleIntegerIndex(obj object.Object) (int64, error) {
 	num, ok := obj.(*object.Integer)
 	if !ok {
 		return 0, fmt.Errorf("expected index to be an INTEGER, got %s", obj.Type())
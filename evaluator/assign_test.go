@@ -125,11 +125,8 @@ func TestEvalAssign(t *testing.T) {
 		// valid hash assignment
 		{
 			env: prefilledEnv(map[string]object.Object{
-				"h": object.NewHash(map[object.HashKey]object.HashPair{
-					object.NewString("a").HashKey(): object.HashPair{
-						Key:   object.NewString("a"),
-						Value: object.NewInteger(1),
-					},
+				"h": object.NewHash([]object.HashPair{
+					{Key: object.NewString("a"), Value: object.NewInteger(1)},
 				}),
 			}),
 			a: newAstAssign(
@@ -144,11 +141,8 @@ func TestEvalAssign(t *testing.T) {
 		// hash assignment with invalid index
 		{
 			env: prefilledEnv(map[string]object.Object{
-				"h": object.NewHash(map[object.HashKey]object.HashPair{
-					object.NewString("a").HashKey(): object.HashPair{
-						Key:   object.NewString("a"),
-						Value: object.NewInteger(1),
-					},
+				"h": object.NewHash([]object.HashPair{
+					{Key: object.NewString("a"), Value: object.NewInteger(1)},
 				}),
 			}),
 			a: newAstAssign(
@@ -162,7 +156,25 @@ func TestEvalAssign(t *testing.T) {
 				},
 				&ast.Integer{Value: 2},
 			),
-			expected: object.NewErrorFormat("expected index to be hashable"),
+			expected: object.NewErrorFormat("type mismatch: STRING + INTEGER"),
+		},
+		// array assignment with an index which fails to evaluate
+		{
+			env: prefilledEnv(map[string]object.Object{
+				"a": object.NewArrayWithObjects(object.NewString("a")),
+			}),
+			a: newAstAssign(
+				&ast.Index{
+					Left: &ast.Identifier{Value: "a"},
+					Index: &ast.Infix{
+						Left:     &ast.Integer{Value: 1},
+						Operator: "/",
+						Right:    &ast.Integer{Value: 0},
+					},
+				},
+				&ast.String{Value: "b"},
+			),
+			expected: object.NewErrorFormat("division by zero not allowed"),
 		},
 
 		// valid string assignment with positive index
@@ -0,0 +1,84 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/flipez/rocket-lang/lexer"
+	"github.com/flipez/rocket-lang/object"
+	"github.com/flipez/rocket-lang/parser"
+)
+
+func benchmarkProgram(b *testing.B, input string) {
+	p := parser.New(lexer.New(input), nil)
+	program, _ := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		b.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if result := Eval(program, object.NewEnvironment()); object.IsError(result) {
+			b.Fatal(result.Inspect())
+		}
+	}
+}
+
+func BenchmarkLoop(b *testing.B) {
+	benchmarkProgram(b, `
+	sum = 0
+	i = 0
+	while (i < 10000)
+	  sum = sum + i * 2
+	  i = i + 1
+	end
+	sum`)
+}
+
+func BenchmarkLoopInFunction(b *testing.B) {
+	benchmarkProgram(b, `
+	def sum(n) {
+	  total = 0
+	  i = 0
+	  while (i < n)
+	    total = total + i * 2
+	    i = i + 1
+	  end
+	  return total
+	}
+	sum(10000)`)
+}
+
+func BenchmarkRecursion(b *testing.B) {
+	benchmarkProgram(b, `
+	def fib(n) {
+	  if (n < 2)
+	    return n
+	  end
+	  return fib(n - 1) + fib(n - 2)
+	}
+	fib(18)`)
+}
+
+func BenchmarkHashStringKeys(b *testing.B) {
+	benchmarkProgram(b, `
+	h = {"count": 0, "total": 0}
+	i = 0
+	while (i < 10000)
+	  h["count"] = h["count"] + 1
+	  h["total"] = h["total"] + i
+	  i = i + 1
+	end
+	h["total"]`)
+}
+
+func BenchmarkHashSymbolKeys(b *testing.B) {
+	benchmarkProgram(b, `
+	h = {count: 0, total: 0}
+	i = 0
+	while (i < 10000)
+	  h[(:count)] = h[(:count)] + 1
+	  h[(:total)] = h[(:total)] + i
+	  i = i + 1
+	end
+	h[(:total)]`)
+}
@@ -0,0 +1,45 @@
+package evaluator
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/object"
+	"github.com/flipez/rocket-lang/token"
+)
+
+// MaxCallDepth is the maximum amount of nested function calls. Deeper
+// recursion fails with an error instead of overflowing the Go stack. Tail
+// calls don't count.
+var MaxCallDepth = 10000
+
+// callStack counts the nested function calls of one Go stack: the one of the
+// program or the one of a generator body.
+type callStack struct {
+	depth int
+}
+
+// calls is the call stack of the code running right now. A generator sets
+// its own one while its body runs, so calls inside a generator don't count
+// for the code which iterates it, and the other way around.
+var calls = &callStack{}
+
+// callFunction calls def like applyFunction, but reports the call site at
+// the position of tok if the call is too deep.
+func callFunction(tok token.Token, site ast.Node, def object.Object, args []object.Object) object.Object {
+	if _, ok := def.(*object.Function); ok && calls.depth >= MaxCallDepth {
+		return object.NewErrorFormat("stack level too deep at %d:%d: %s", tok.LineNumber, tok.LinePosition, site)
+	}
+	return applyFunction(def, args)
+}
+
+// tailCall is returned instead of calling a function in tail position, the
+// function returning it calls the next function in its place.
+type tailCall struct {
+	function *object.Function
+	args     []object.Object
+}
+
+func (tc *tailCall) Type() object.ObjectType { return "TAIL_CALL" }
+func (tc *tailCall) Inspect() string         { return "tail call" }
+func (tc *tailCall) InvokeMethod(method string, env object.Environment, args ...object.Object) object.Object {
+	return nil
+}
@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/object"
+)
+
+// evalComprehension builds the array or hash of a comprehension. Every
+// iteration of a clause gets a fresh scope, so the loop variables hide
+// outer ones and closures keep the values of their own iteration.
+func evalComprehension(node *ast.Comprehension, env *object.Environment) object.Object {
+	if node.Key == nil {
+		elements := []object.Object{}
+		err := evalComprehensionClauses(node.Clauses, env, func(scope *object.Environment) object.Object {
+			value := Eval(node.Value, scope)
+			if object.IsError(value) {
+				return value
+			}
+			elements = append(elements, value)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return object.NewArray(elements)
+	}
+
+	hash := object.NewHash(nil)
+	err := evalComprehensionClauses(node.Clauses, env, func(scope *object.Environment) object.Object {
+		key := Eval(node.Key, scope)
+		if object.IsError(key) {
+			return key
+		}
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return object.NewErrorFormat("unusable as hash key: %s", key.Type())
+		}
+
+		value := Eval(node.Value, scope)
+		if object.IsError(value) {
+			return value
+		}
+		hash.Set(hashKey, value)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return hash
+}
+
+// evalComprehensionClauses calls emit for every combination of values of
+// the clauses which passes their conditions. It stops at the first error.
+func evalComprehensionClauses(clauses []ast.ComprehensionClause, env *object.Environment, emit func(*object.Environment) object.Object) object.Object {
+	if len(clauses) == 0 {
+		return emit(env)
+	}
+
+	clause := clauses[0]
+	val := Eval(clause.Foreach.Value, env)
+	if object.IsError(val) {
+		return val
+	}
+
+	helper, ok := val.(object.Iterable)
+	if !ok {
+		return object.NewErrorFormat("%s object doesn't implement the Iterable interface", val.Type())
+	}
+
+	iterator := helper.Iter()
+	defer iterator.Close()
+
+	for ret, idx, ok := iterator.Next(); ok; ret, idx, ok = iterator.Next() {
+		if object.IsError(ret) {
+			return ret
+		}
+		ret, idx = foreachItem(clause.Foreach, val, ret, idx)
+
+		scope := object.NewScopeEnvironment(env, clause.Locals)
+		if clause.Foreach.Index != "" {
+			scope.Define(clause.Foreach.Index, idx)
+		}
+		if clause.Foreach.Pattern != nil {
+			for _, name := range patternNames(clause.Foreach.Pattern) {
+				scope.Define(name, object.NULL)
+			}
+			if err := destructure(clause.Foreach.Pattern, ret, scope); err != nil {
+				return err
+			}
+		} else {
+			scope.Define(clause.Foreach.Ident, ret)
+		}
+
+		if clause.Condition != nil {
+			ok, err := evalCondition(clause.Condition, false, scope)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		if err := evalComprehensionClauses(clauses[1:], scope, emit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patternNames returns the names of the variables a destructuring pattern
+// assigns to.
+func patternNames(pattern ast.Expression) []string {
+	switch p := pattern.(type) {
+	case *ast.Identifier:
+		return []string{p.Value}
+	case *ast.Splat:
+		return patternNames(p.Value)
+	case *ast.Array:
+		names := []string{}
+		for _, element := range p.Elements {
+			names = append(names, patternNames(element)...)
+		}
+		return names
+	case *ast.Hash:
+		names := []string{}
+		for _, pair := range p.Pairs {
+			names = append(names, patternNames(pair.Value)...)
+		}
+		return names
+	}
+	return nil
+}
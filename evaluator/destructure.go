@@ -0,0 +1,103 @@
+package evaluator
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/object"
+)
+
+// destructure assigns the parts of value to the targets in pattern. An array
+// pattern needs an array with exactly one value per target, a splat target
+// takes all values that are left over. A hash pattern needs a hash with all
+// of its keys. The returned error is nil on success.
+func destructure(pattern ast.Expression, value object.Object, env *object.Environment) object.Object {
+	switch p := pattern.(type) {
+	case *ast.Array:
+		return destructureArray(p, value, env)
+	case *ast.Hash:
+		return destructureHash(p, value, env)
+	default:
+		if ret := assign(pattern, "", value, env); object.IsError(ret) {
+			return ret
+		}
+		return nil
+	}
+}
+
+func destructureArray(pattern *ast.Array, value object.Object, env *object.Environment) object.Object {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return object.NewErrorFormat("cannot destructure %s into %s", value.Type(), pattern)
+	}
+
+	splat := -1
+	for i, target := range pattern.Elements {
+		if _, ok := target.(*ast.Splat); ok {
+			splat = i
+		}
+	}
+
+	targets, values := len(pattern.Elements), len(array.Elements)
+	if splat == -1 && values != targets {
+		return object.NewErrorFormat("wrong number of values to destructure into %s. got=%d, want=%d", pattern, values, targets)
+	}
+	if splat != -1 && values < targets-1 {
+		return object.NewErrorFormat("wrong number of values to destructure into %s. got=%d, want at least %d", pattern, values, targets-1)
+	}
+
+	// copy the elements first, the targets may change the array itself
+	elements := make([]object.Object, values)
+	copy(elements, array.Elements)
+
+	for i, target := range pattern.Elements {
+		var err object.Object
+		switch {
+		case i == splat:
+			rest := make([]object.Object, values-targets+1)
+			copy(rest, elements[i:])
+			err = destructure(target.(*ast.Splat).Value, object.NewArray(rest), env)
+		case splat != -1 && i > splat:
+			err = destructure(target, elements[values-targets+i], env)
+		default:
+			err = destructure(target, elements[i], env)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func destructureHash(pattern *ast.Hash, value object.Object, env *object.Environment) object.Object {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return object.NewErrorFormat("cannot destructure %s into a hash pattern", value.Type())
+	}
+
+	for _, pair := range pattern.Pairs {
+		key := Eval(pair.Key, env)
+		if object.IsError(key) {
+			return key
+		}
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return object.NewErrorFormat("unusable as hash key: %s", key.Type())
+		}
+
+		element, ok := hash.Get(hashKey)
+		// `{name} = h` also takes the string key "name", like `h.name`
+		if symbol, isSymbol := key.(*object.Symbol); !ok && isSymbol {
+			element, ok = hash.Get(object.NewString(symbol.Name))
+		}
+		if !ok {
+			return object.NewErrorFormat("key %s is missing to destructure the hash", key.Inspect())
+		}
+
+		if err := destructure(pair.Value, element, env); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,8 +3,17 @@ package evaluator
 import (
 	"github.com/flipez/rocket-lang/ast"
 	"github.com/flipez/rocket-lang/object"
+	"github.com/flipez/rocket-lang/parser"
 )
 
+// init connects the packages the evaluator depends on back to it: object
+// methods call functions through object.ApplyFunction and the parser asks
+// parser.Predefined for the builtin names when it reports undefined variables.
+func init() {
+	object.ApplyFunction = applyFunction
+	parser.Predefined = isPredefined
+}
+
 func Eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 
@@ -20,12 +29,20 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalForeach(node, env)
 	case *ast.While:
 		return evalWhile(node, env)
+	case *ast.Until:
+		return evalUntil(node, env)
+	case *ast.Loop:
+		return evalLoop(nil, false, node.Body, node.Locals, env)
+	case *ast.Modifier:
+		return evalModifier(node, env)
 	case *ast.Return:
 		val := Eval(node.ReturnValue, env)
 		if object.IsError(val) {
 			return val
 		}
 		return object.NewReturnValue(val)
+	case *ast.Yield:
+		return evalYield(node, env)
 
 	// Expressions
 	case *ast.Integer:
@@ -38,6 +55,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			env,
 			node.Body,
 		)
+		function.Generator = node.Generator
+		function.Locals = node.Locals
 
 		if node.Name != "" {
 			env.Set(node.Name, function)
@@ -48,6 +67,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalImport(node, env)
 	case *ast.String:
 		return object.NewString(node.Value)
+	case *ast.Symbol:
+		return object.NewSymbol(node.Value)
 	case *ast.Array:
 		elements := evalExpressions(node.Elements, env)
 		if len(elements) == 1 && object.IsError(elements[0]) {
@@ -56,10 +77,19 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return object.NewArray(elements)
 	case *ast.Hash:
 		return evalHash(node, env)
+	case *ast.Comprehension:
+		return evalComprehension(node, env)
 
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
+	case *ast.Null:
+		return object.NULL
 
+	case *ast.MethodReference:
+		return object.NewMethodReference(node.Name)
+
+	case *ast.Splat:
+		return object.NewErrorFormat("splat `%s` is only allowed in calls, literals and when destructuring", node)
 	case *ast.Prefix:
 		right := Eval(node.Right, env)
 		if object.IsError(right) {
@@ -72,6 +102,18 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if object.IsError(left) {
 			return left
 		}
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalInfixExpression(node, left, env)
+		}
+		if node.Operator == "??" {
+			if left != object.NULL {
+				return left
+			}
+			return Eval(node.Right, env)
+		}
+		if node.Operator == "|>" {
+			return evalPipe(left, node.Right, env)
+		}
 		right := Eval(node.Right, env)
 		if object.IsError(right) {
 			return right
@@ -80,6 +122,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 
 	case *ast.If:
 		return evalIf(node, env)
+	case *ast.Unless:
+		return evalUnless(node, env)
 	case *ast.Ternary:
 		return evalTernary(node, env)
 
@@ -93,7 +137,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return args[0]
 		}
 
-		return applyFunction(function, args)
+		if fn, ok := function.(*object.Function); ok && node.Tail && !fn.Generator {
+			return &tailCall{function: fn, args: args}
+		}
+		return callFunction(node.Token, node, function, args)
 
 	case *ast.Index:
 		left := Eval(node.Left, env)
@@ -146,8 +193,30 @@ func applyFunction(def object.Object, args []object.Object) object.Object {
 	switch def := def.(type) {
 	case *object.Function:
 		extendedEnv := extendFunctionEnv(def, args)
-		evaluated := Eval(def.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
+		if def.Generator {
+			return newGenerator(def.Body, extendedEnv)
+		}
+
+		// the stack is kept, a generator closed while this call waits in a
+		// yield unwinds it after calls was switched back
+		stack := calls
+		if stack.depth >= MaxCallDepth {
+			return object.NewErrorFormat("stack level too deep")
+		}
+		stack.depth++
+		defer func() { stack.depth-- }()
+
+		// tail calls are run here instead of growing the stack
+		for {
+			evaluated := unwrapReturnValue(Eval(def.Body, extendedEnv))
+			tail, ok := evaluated.(*tailCall)
+			if !ok {
+				return evaluated
+			}
+
+			def = tail.function
+			extendedEnv = extendFunctionEnv(def, tail.args)
+		}
 
 	case *object.Builtin:
 		return def.Fn(args...)
@@ -158,10 +227,15 @@ func applyFunction(def object.Object, args []object.Object) object.Object {
 }
 
 func extendFunctionEnv(def *object.Function, args []object.Object) *object.Environment {
-	env := object.NewEnclosedEnvironment(def.Env)
+	env := object.NewScopeEnvironment(def.Env, def.Locals)
 
+	// missing arguments are null, e.g. a block which was not given
 	for paramIdx, param := range def.Parameters {
-		env.Set(param.Value, args[paramIdx])
+		if paramIdx < len(args) {
+			env.Define(param.Value, args[paramIdx])
+		} else {
+			env.Define(param.Value, object.NULL)
+		}
 	}
 
 	return env
@@ -179,6 +253,15 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	var result []object.Object
 
 	for _, e := range exps {
+		if splat, ok := e.(*ast.Splat); ok {
+			values := evalSpread(splat, env)
+			if len(values) == 1 && object.IsError(values[0]) {
+				return values
+			}
+			result = append(result, values...)
+			continue
+		}
+
 		evaluated := Eval(e, env)
 		if object.IsError(evaluated) {
 			return []object.Object{evaluated}
@@ -189,6 +272,36 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return result
 }
 
+// evalSpread returns all values of the iterable splat evaluates to. Like
+// evalExpressions it returns a single error on failure.
+func evalSpread(splat *ast.Splat, env *object.Environment) []object.Object {
+	value := Eval(splat.Value, env)
+	if object.IsError(value) {
+		return []object.Object{value}
+	}
+
+	if array, ok := value.(*object.Array); ok {
+		return array.Elements
+	}
+
+	iterable, ok := value.(object.Iterable)
+	if !ok {
+		return []object.Object{object.NewErrorFormat("cannot spread %s, it is not iterable", value.Type())}
+	}
+
+	iterator := iterable.Iter()
+	defer iterator.Close()
+
+	values := []object.Object{}
+	for element, _, ok := iterator.Next(); ok; element, _, ok = iterator.Next() {
+		if object.IsError(element) {
+			return []object.Object{element}
+		}
+		values = append(values, element)
+	}
+	return values
+}
+
 func evalBangOperatorExpression(right object.Object) object.Object {
 	switch right {
 	case object.TRUE:
@@ -203,12 +316,14 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 }
 
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
+	switch right := right.(type) {
+	case *object.Integer:
+		return object.NewInteger(-right.Value)
+	case *object.Float:
+		return object.NewFloat(-right.Value)
+	default:
 		return object.NewErrorFormat("unknown operator: -%s", right.Type())
 	}
-
-	value := right.(*object.Integer).Value
-	return object.NewInteger(-value)
 }
 
 func nativeBoolToBooleanObject(input bool) *object.Boolean {
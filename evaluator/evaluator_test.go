@@ -1,8 +1,15 @@
 package evaluator
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"testing"
+	"time"
 
+	"github.com/flipez/rocket-lang/ast"
 	"github.com/flipez/rocket-lang/lexer"
 	"github.com/flipez/rocket-lang/object"
 	"github.com/flipez/rocket-lang/parser"
@@ -32,6 +39,25 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"5 ➕ 5 ➕ 5 ➕ 5 - 10", 10},
 		{"5 % 5", 0},
 		{"5 % 4", 1},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"7 ** 3", 343},
+		{"3 ** 0", 1},
+		{"2 ** 62", 4611686018427387904},
+		{"1 ** 5000000000", 1},
+		{"(-1) ** 5000000001", -1},
+		{"0 ** 5000000000", 0},
+		{"6 & 3", 2},
+		{"6 | 3", 7},
+		{"6 ^ 3", 5},
+		{"1 << 4", 16},
+		{"256 >> 2", 64},
+		{"0xFF + 0b1010 + 0o10", 273},
+		{"1_000 * 1_000", 1000000},
+		{"1 + 2 << 1", 6},
+		{`"abc123" =~ regex("[0-9]")`, 3},
+		{`regex("b") =~ "abc"`, 1},
 	}
 
 	for _, tt := range tests {
@@ -85,6 +111,15 @@ func TestEvalBooleanExpression(t *testing.T) {
 		{"3 > 4 ? false : true", true},
 		{"a = true ? (false ? 0 : true) : 0; a", true},
 		{"[1] + [1] == [1, 1]", true},
+		{"true && true", true},
+		{"true && false", false},
+		{"false || true", true},
+		{"false || false", false},
+		{"1 < 2 && 2 < 3", true},
+		{"1 > 2 || 2 > 3", false},
+		{"false && nope()", false},
+		{"true || nope()", true},
+		{"a = []; a.size() > 0 && a[0] == 1", false},
 	}
 
 	for _, tt := range tests {
@@ -128,6 +163,14 @@ func TestIfElseExpressions(t *testing.T) {
 		{"if (1 > 2) { 10 } else { 20 }", 20},
 		{"if (1 < 2) { 10 } else { 20 }", 10},
 		{"if (1 < 2) \n 10 \n else \n 20 end", 10},
+		{"if (false) \n 10 \n elsif (true) \n 20 \n else \n 30 end", 20},
+		{"if (false) \n 10 \n elsif (false) \n 20 \n else \n 30 end", 30},
+		{"if (false) { 10 } elsif (false) { 20 }", nil},
+		{"if (false) { 10 } else if (true) { 20 } else { 30 }", 20},
+		{"unless (false) { 10 }", 10},
+		{"unless (true) { 10 }", nil},
+		{"unless (1 < 2) \n 10 \n else \n 20 end", 20},
+		{"x = 1; if (false) { x = 2 }\nx", 1},
 	}
 
 	for _, tt := range tests {
@@ -141,6 +184,139 @@ func TestIfElseExpressions(t *testing.T) {
 	}
 }
 
+func TestLoops(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"a = 0; while (a < 3) { a = a + 1 }; a", 3},
+		{"b = 0; until (b == 3) \n b = b + 1 end; b", 3},
+		{"c = 5; until (true) { c = 0 }; c", 5},
+		{"def f() { n = 0; loop { n = n + 1; if (n == 4) \n return n end } }; f()", 4},
+		{"def g() { m = 0\n loop \n m = m + 2\n return m if m > 5\n end }; g()", 6},
+		{"d = 0\nd = d + 1 while d < 10\nd", 10},
+		{"e = 0\ne = e + 1 until e == 7\ne", 7},
+		{"f = 1\nf = 2 if false\nf", 1},
+		{"h = 1\nh = 2 unless false\nh", 2},
+		{"def k(x) { return 1 if x\n 2 }; k(true) + k(false)", 3},
+		{"until (1 + true) { 1 }", "type mismatch: INTEGER + BOOLEAN"},
+		{"1 if 1 + true", "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestNullHandling(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"null", nil},
+		{"nil", nil},
+		{"null == nil", true},
+		{"null != null", false},
+		{"null == false", false},
+		{"[null] == [null]", true},
+		{`{"a": 1}["b"] == null`, true},
+		{"[1][3] ?? 2", 2},
+		{"1 ?? 2", 1},
+		{"false ?? 2", false},
+		{"null ?? null", nil},
+		{"null ?? 1 + true", "type mismatch: INTEGER + BOOLEAN"},
+		{"1 ?? 1 + true", 1},
+		{`a = null; a&.size()`, nil},
+		{`a = [1, 2]; a&.size()`, 2},
+		{`a = null; a&.size().nope()`, "undefined method `.nope()` for NULL"},
+		{`a = null; a&.size(1 + true)`, nil},
+		{`a = null; a.size()`, "undefined method `.size()` for NULL"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestDotAccess(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`h = {"a": 1}; h.a`, 1},
+		{`h = {"a": 1}; h.b`, nil},
+		{`h = {"keys": 1}; h.keys.size`, 1},
+		{`h = {}; h.a = 2; h["a"]`, 2},
+		{`h = {"a": 1}; h.a += 2; h.a`, 3},
+		{`h = {"keys": 1}; h.keys = 2; h["keys"]`, 2},
+		{`h = {"double": def(x) { return x * 2 }}; h.double(3)`, 6},
+		{`h = {"a": 1}; h.a()`, "undefined method `.a()` for HASH"},
+		{`[1, 2].size`, 2},
+		{`"abc".size`, 3},
+		{`1.nope`, "undefined method `.nope` for INTEGER"},
+		{`a = [1]; a.b = 1`, "cannot assign to `.b` of ARRAY"},
+		{`a = null; a&.size`, nil},
+		{`fs.exists?("/")`, true},
+		{`path.join("a", "b").size`, 3},
+		{`path.type`, "MODULE"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			if str, ok := evaluated.(*object.String); ok {
+				testStringObject(t, str, expected)
+				continue
+			}
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -191,14 +367,14 @@ func TestErrorHandling(t *testing.T) {
 		},
 		{`"Hello" - "World"`, "unknown operator: STRING - STRING"},
 		{`{"name": "Monkey"}[def(x) { x }];`, "unusable as hash key: FUNCTION"},
-		{"🔥 != 👍", "identifier not found: IDENT"},
+		{"🔥 != 👍", "identifier not found: 🔥"},
 		{"5 % 0", "division by zero not allowed"},
 		{"5 % 0 ? true : false", "division by zero not allowed"},
 		{"(4 > 5 ? true).nope()", "undefined method `.nope()` for NULL"},
 		{"if (5 % 0)\n puts(true)\nend", "division by zero not allowed"},
 		{"a = {(5%0): true}", "division by zero not allowed"},
 		{"a = {true: (5%0)}", "division by zero not allowed"},
-		{"def test() { puts(true) }; a = {test: true}", "unusable as hash key: FUNCTION"},
+		{"def test() { puts(true) }; a = {(test): true}", "unusable as hash key: FUNCTION"},
 		{"import(true)", "Import Error: invalid import path '&{%!s(bool=true)}'"},
 		{"import(5%0)", "division by zero not allowed"},
 		{`import("fixtures/nope")`, "Import Error: no module named 'fixtures/nope' found"},
@@ -208,6 +384,14 @@ func TestErrorHandling(t *testing.T) {
 		},
 		{"def test() { puts(true) }; test[1]", "index operator not supported: FUNCTION"},
 		{"[1] - [1]", "unknown operator: ARRAY - ARRAY"},
+		{"true && nope()", "identifier not found: nope"},
+		{"1.5 & 1", "unknown operator: FLOAT & FLOAT"},
+		{"1 << -1", "negative shift amount not allowed"},
+		{"a += 1", "identifier not found: a"},
+		{`a = "b"; a -= 1`, "type mismatch: STRING - INTEGER"},
+		{`"a" =~ "a"`, "unknown operator: STRING =~ STRING"},
+		{"set([1]) + set([2])", "unknown operator: SET + SET"},
+		{"set([1]) | [2]", "type mismatch: SET | ARRAY"},
 	}
 
 	for _, tt := range tests {
@@ -234,6 +418,14 @@ func TestAssignStatements(t *testing.T) {
 		{"a = 5 * 5; a;", 25},
 		{"a = 5; b = a; b;", 5},
 		{"a = 5; b = a; c = a + b + 5; c;", 15},
+		{"a = 5; a += 2; a;", 7},
+		{"a = 5; a -= 2; a;", 3},
+		{"a = 5; a *= 2; a;", 10},
+		{"a = 10; a /= 2; a;", 5},
+		{"a = 10; a %= 3; a;", 1},
+		{"a = [1, 2]; a[1] += 3; a[1];", 5},
+		{`h = {"a": 1}; h["a"] *= 4; h["a"];`, 4},
+		{"a = 1; b = a += 2; b;", 3},
 	}
 
 	for _, tt := range tests {
@@ -241,6 +433,373 @@ func TestAssignStatements(t *testing.T) {
 	}
 }
 
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"a, b = [1, 2]; a * 10 + b", 12},
+		{"a, b = 1, 2; a, b = b, a; a * 10 + b", 21},
+		{"first, *rest = [1, 2, 3]; rest", "[2, 3]"},
+		{"first, *rest = [1]; rest", "[]"},
+		{"*init, last = [1, 2, 3]; init", "[1, 2]"},
+		{"x, *mid, y = [1, 2, 3, 4]; mid", "[2, 3]"},
+		{"x, *mid, y = [1, 2, 3, 4]; y", 4},
+		{"[p, [q, r]] = [1, [2, 3]]; p + q + r", 6},
+		{"a = [1, 2]\n[b, c] = a\nb + c", 3},
+		{"d = [1, 2]\n*e, f = d\nf", 2},
+		{`{name, age} = {"name": "Anna", "age": 24}; age`, 24},
+		{`{"name": n, "tags": [t, *ts]} = {"name": "x", "tags": [1, 2]}; ts`, "[2]"},
+		{`h = {}; h.a, h["b"] = 1, 2; h.a + h.b`, 3},
+		{"g = [0, 0]; g[0], g[1] = 1, 2; g", "[1, 2]"},
+		{"def minmax(a) { return a.first(), a.last() }; lo, hi = minmax([1, 5, 3]); lo * 10 + hi", 13},
+		{"s = 0; foreach [k, v] in [[1, 2], [3, 4]] { s = s + k * v }; s", 14},
+		{"s = 0; foreach i, [k, v] in [[1, 2], [3, 4]] { s = s + i + k }; s", 5},
+		{`s = ""; foreach {name} in [{"name": "a"}, {"name": "b"}] { s = s + name }; s`, "ab"},
+		{"a, b = [1]", "wrong number of values to destructure into [a, b]. got=1, want=2"},
+		{"a, b = 1, 2, 3", "wrong number of values to destructure into [a, b]. got=3, want=2"},
+		{"a, *b, c = [1]", "wrong number of values to destructure into [a, *b, c]. got=1, want at least 2"},
+		{"a, b = 1", "cannot destructure INTEGER into [a, b]"},
+		{`{a} = [1]`, "cannot destructure ARRAY into a hash pattern"},
+		{`{a} = {"b": 1}`, `key :a is missing to destructure the hash`},
+		{"foreach [a, b] in [[1]] { a }", "wrong number of values to destructure into [a, b]. got=1, want=2"},
+		{"*a", "splat `*a` is only allowed in calls, literals and when destructuring"},
+		{`n = 1; h = {n}; h[(:n)]`, 1},
+		{`{name, age} = {name: "bob", age: 3}; age`, 3},
+		{`{name: n} = {"name": "bob"}; n`, "bob"},
+		{`{"name": n} = {name: "bob"}`, `key "name" is missing to destructure the hash`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			switch obj := evaluated.(type) {
+			case *object.Array:
+				if obj.Inspect() != expected {
+					t.Errorf("%q: wrong array. want=%s, got=%s", tt.input, expected, obj.Inspect())
+				}
+			case *object.String:
+				testStringObject(t, obj, expected)
+			case *object.Error:
+				if obj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, obj.Message)
+				}
+			default:
+				t.Errorf("%q: unexpected result %T(%+v)", tt.input, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestLambdasAndBlocks(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"double = ->(x) { x * 2 }; double(4)", 8},
+		{"f = -> { 7 }; f()", 7},
+		{"add = |a, b| a + b; add(2, 3)", 5},
+		{"f = || 42; f()", 42},
+		{"y = 10; f = |x| x + y; f(1)", 11},
+		{"def twice(x, f) { return f(f(x)) }; twice(3) { |v| v * 3 }", 27},
+		{"def twice(x, f) { return f(f(x)) }; twice(3) do |v| v + 1 end", 5},
+		{"def call(f) { f() }; call() { 5 }", 5},
+		{"def with(x, f) { if (f) { return f(x) }; return x }; with(1)", 1},
+		{"def with(x, f) { if (f) { return f(x) }; return x }; with(1) { |v| v + 1 }", 2},
+		{"def pair(f) { f(1, 2) }; pair() { |a| a }", 1},
+		{"def pair(f) { f(1) }; pair() { |a, b| b }", nil},
+		{"3.iter().map { |i| i * 2 }.to_a()", "[0, 2, 4]"},
+		{"3.iter().select(|i| i > 0).to_a()", "[1, 2]"},
+		{"a = []; foreach i in (2.iter().map { |i| i + 1 }) { a.yoink(i) }; a", "[1, 2]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case nil:
+			testNullObject(t, evaluated)
+		case string:
+			arr, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("%q: object is not Array. got=%T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if arr.Inspect() != expected {
+				t.Errorf("%q: wrong array. want=%s, got=%s", tt.input, expected, arr.Inspect())
+			}
+		}
+	}
+}
+
+func TestPipeAndComposition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"def double(x) { x * 2 }; 3 |> double()", 6},
+		{"def double(x) { x * 2 }; def add(a, b) { a + b }; 3 |> double |> add(1)", 7},
+		{"def add(a, b) { a * 10 + b }; 1 |> add(2)", 12},
+		{"1 + 2 |> |x| x * 10", 30},
+		{`"abc" |> &:upcase`, "ABC"},
+		{`"a/b" |> path.basename()`, "b"},
+		{`h = {"f": |x| x + 1}; 1 |> h.f()`, 2},
+		{"[1, 2] |> &:size", 2},
+		{"f = (|x| x * 2) >> (|x| x + 1); f(5)", 11},
+		{"f = (|x| x * 2) << (|x| x + 1); f(5)", 12},
+		{`f = &:upcase >> &:reverse; f("ab")`, "BA"},
+		{"1 << 3", 8},
+		{"def add(a, b, c) { a * 100 + b * 10 + c }; p = partial(add, 1, 2); p(3)", 123},
+		{"def add(a, b, c) { a * 100 + b * 10 + c }; partial(add)(1, 2, 3)", 123},
+		{`j = partial(path.join, "a"); j("b", "c")`, "a/b/c"},
+		{"def add(a, b, c) { a * 100 + b * 10 + c }; curry(add)(1)(2)(3)", 123},
+		{`curry(path.join, 2)("a")("b")`, "a/b"},
+		{"def add(a, b) { a * 10 + b }; inc = curry(add)(1); 2 |> inc", 12},
+		{"def add(a, b) { a * 10 + b }; c = curry(add); one = c(1); two = c(2); one(5) * 100 + two(6)", 1526},
+		{"def add(a, b) { a * 10 + b }; curry(add)(1, 2)", "wrong number of arguments to curried function. got=2, want=1"},
+		{"def add(a, b) { a * 10 + b }; curry(add)(1)()", "wrong number of arguments to curried function. got=0, want=1"},
+		{"f = (|x| x * 2) >> (|x| x + 1); f.type()", "BUILTIN"},
+		{"def add(a, b) { a + b }; partial(add, 1).type()", "BUILTIN"},
+		{"f = (|x| x.nope()) >> (|x| x + 1); f(1)", "undefined method `.nope()` for INTEGER"},
+		{"1 |> 2", "not a function: INTEGER"},
+		{"def add(a, b) { a + b }; partial(add, 1, 2, 3)", "too many arguments to partially apply. got=3, want at most 2"},
+		{"partial(1)", "argument 1 to `partial` must be FUNCTION|BUILTIN, got=INTEGER"},
+		{"curry(puts)", "argument 1 to `curry` must be FUNCTION, got=BUILTIN"},
+		{"curry(puts, 0)", "cannot curry a function without arguments"},
+		{"1 |> &:nope", "undefined method `.nope()` for INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			switch obj := evaluated.(type) {
+			case *object.String:
+				testStringObject(t, obj, expected)
+			case *object.Error:
+				if obj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, obj.Message)
+				}
+			default:
+				t.Errorf("%q: unexpected result %T(%+v)", tt.input, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestResolvedVariables(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"def even(n) { n == 0 ? 1 : odd(n - 1) }; def odd(n) { n == 0 ? 0 : even(n - 1) }; even(10)", 1},
+		{"x = 1; def f() { x = x + 1 }; f(); x", 2},
+		{"x = 1; def f(x) { x = 5 }; f(1); x", 1},
+		{"def f() { later }; later = 3; f()", 3},
+		{"def f() { x = 1 }; f(); x", "identifier not found: x"},
+		{"def f() { x = 1; return x }; f(); x; x = 5", "identifier not found: x"},
+		{"def f() { x = 1; x }; y = f(); x = 5; y * 10 + x", 15},
+		{"def f() { x = 1 }; x = 0; f(); x", 1},
+		{"def inc() { counter = counter + 1 }; counter = 0; inc(); inc(); counter", 2},
+		{"def f() { foreach i in 2 { x = i }; x }; f() * 10 + (x = 3)", "identifier not found: x"},
+		{"def f() { def g() { x = 1 }; g(); x }; x = 0; f()", 1},
+		{"x = 0; def f() { x = 1 }; f(); x", 1},
+		{"n = 0; foreach i in [1, 2, 3] { n += i }; n", 6},
+		{"def f() { n = 0; foreach i in [1, 2, 3] { n += i }; n }; f()", 6},
+		{"fs = []; foreach i in 3 { fs.yoink(|| i) }; fs[0]() * 100 + fs[1]() * 10 + fs[2]()", 12},
+		{"fs = []; foreach i in 2 { n = i; fs.yoink(|| n) }; fs[0]() * 10 + fs[1]()", 1},
+		{"s = 0; foreach i in 3 { if (i > 0) { s += prev }; prev = i * 10 }; s", 10},
+		{"fs = []; foreach i in 2 { foreach j in 1 { fs.yoink(|| i) } }; fs[0]() * 10 + fs[1]()", 1},
+		{"a = 0; while (a < 3) { b = a; a += 1 }; a", 3},
+		{"a = 0; a += 1 while (a < 5); a", 5},
+		{"c = def() { n = 0; def() { n += 1 } }(); c(); c()", 2},
+		{"def f(a) { [a, b] = [a, 2]; a + b }; f(1)", 3},
+		{"x = 10; [x * y foreach x in [1, 2] foreach y in [x] if y > 1][0] + x", 14},
+		{"def f(n) { def g() { n * 2 }; g() }; f(4)", 8},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestProgramsSharingGlobals(t *testing.T) {
+	env := object.NewEnvironment()
+	var globals []string
+
+	var evaluated object.Object
+	for _, line := range []string{"a = 1", "b = 2", "def f() { a + b }", "a = 10", "f()"} {
+		p := parser.New(lexer.New(line), globals)
+		var program *ast.Program
+		program, globals = p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%q: unexpected parser errors %v", line, p.Errors())
+		}
+		evaluated = Eval(program, env)
+	}
+
+	testIntegerObject(t, evaluated, 12)
+}
+
+func TestTailCalls(t *testing.T) {
+	maxCallDepth := MaxCallDepth
+	MaxCallDepth = 100
+	defer func() { MaxCallDepth = maxCallDepth }()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"def count(n, acc) { if (n == 0) { return acc }; return count(n - 1, acc + 1) }; count(1000, 0)", 1000},
+		{"def count(n) { if (n == 0) { 0 } else { count(n - 1) } }; count(1000)", 0},
+		{"def count(n) { unless (n > 0) { return 0 }; count(n - 1) }; count(1000)", 0},
+		{"def even(n) { n == 0 ? 1 : odd(n - 1) }; def odd(n) { n == 0 ? 0 : even(n - 1) }; even(1001)", 0},
+		{"count = |n| n == 0 ? 0 : count(n - 1); count(1000)", 0},
+		{"def count(n) { foreach i in 1 { return n == 0 ? 0 : count(n - 1) } }; count(1000)", 0},
+		{"def count(n) { return 0 if (n == 0); return count(n - 1) }; count(1000)", 0},
+		{"def f(n) { if (n == 0) { return 0 }; return 1 + f(n - 1) }; f(50)", 50},
+		{"def f(n) { if (n == 0) { return 0 }; return 1 + f(n - 1) }; f(1000)", "stack level too deep at 0:50: f((n - 1))"},
+		{"def f(n) { x = f(n - 1); x }; f(1)", "stack level too deep at 0:17: f((n - 1))"},
+		{`m = {"f": def(n) { 1 + m.f(n) }}; m.f(1)`, "stack level too deep at 0:25: m.f(n)"},
+		{"def f(n) { [n].each { |x| f(x) } }; f(1)", "stack level too deep"},
+		{"def f(n) { 1 + (n |> f()) }; f(1)", "stack level too deep at 0:23: f()"},
+		{"def deep(n) { n == 0 ? 0 : 1 + deep(n - 1) }; def gen() { yield deep(60) }; it = gen(); def use(n) { n == 0 ? it.next() : 1 + use(n - 1) }; use(60)", 120},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("%q: no error object returned. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestComprehensions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[x * 2 foreach x in [1, 2, 3] if x > 1]", "[4, 6]"},
+		{"[x foreach x in 6 if x % 2 == 0]", "[0, 2, 4]"},
+		{"[x foreach x in []]", "[]"},
+		{"[[x, y] foreach x in 3 foreach y in 3 if x < y]", "[[0, 1], [0, 2], [1, 2]]"},
+		{"[y foreach x in [[1, 2], [3]] foreach y in x]", "[1, 2, 3]"},
+		{"[i foreach i, v in [5, 6]]", "[0, 1]"},
+		{"[a + b foreach [a, b] in [[1, 2], [3, 4]]]", "[3, 7]"},
+		{`[k foreach k, v in {"a": 1, "b": 2} if v > 1]`, `["b"]`},
+		{`{k: v * 10 foreach k, v in {"a": 1, "b": 2}}`, `{"a": 10, "b": 20}`},
+		{"{x: x * x foreach x in 3}", "{0: 0, 1: 1, 2: 4}"},
+		{"{x % 2: x foreach x in 5}", "{0: 4, 1: 3}"},
+		{"def gen() { yield 1; yield 2 }; [x + 1 foreach x in gen()]", "[2, 3]"},
+		{"x = 5; [x foreach x in 2]; x", 5},
+		{"x = 5; [a foreach [x, a] in [[1, 2]]]; x", 5},
+		{"fs = [|| x foreach x in 3]; fs[0]() * 100 + fs[1]() * 10 + fs[2]()", 12},
+		{"[x foreach x in 1.5]", "FLOAT object doesn't implement the Iterable interface"},
+		{"[x.nope() foreach x in 2]", "undefined method `.nope()` for INTEGER"},
+		{"[x foreach x in 2 if x.nope()]", "undefined method `.nope()` for INTEGER"},
+		{"{[x]: 1 foreach x in 1}", "{[0]: 1}"},
+		{"{def() {}: 1 foreach x in 1}", "unusable as hash key: FUNCTION"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			switch obj := evaluated.(type) {
+			case *object.Array, *object.Hash:
+				if obj.Inspect() != expected {
+					t.Errorf("%q: wrong result. want=%s, got=%s", tt.input, expected, obj.Inspect())
+				}
+			case *object.Error:
+				if obj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, obj.Message)
+				}
+			default:
+				t.Errorf("%q: unexpected result %T(%+v)", tt.input, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"def add(a, b, c) { return a * 100 + b * 10 + c }; add(*[1, 2, 3])", 123},
+		{"def add(a, b, c) { return a * 100 + b * 10 + c }; add(1, *[2], 3)", 123},
+		{"a = [1, 2]; [*a, *a, 3]", "[1, 2, 1, 2, 3]"},
+		{"[*[]]", "[]"},
+		{`[*"ab", *2]`, `["a", "b", 0, 1]`},
+		{"def gen() { yield 1; yield 2 }; [*gen()]", "[1, 2]"},
+		{`path.join(*["a", "b"])`, "a/b"},
+		{"[1, 2].yoink(*[3]); 1", 1},
+		{`d = {"a": 1, "b": 2}; o = {"b": 3}; h = {**d, **o}; h["a"] * 10 + h["b"]`, 13},
+		{`d = {"a": 1}; h = {**d, "a": 2}; h["a"]`, 2},
+		{`d = {"a": 1}; h = {"a": 2, **d}; h["a"]`, 1},
+		{`d = {"a": 1}; h = {**d}; h["b"] = 2; d.keys().size()`, 1},
+		{"[*1.5]", "cannot spread FLOAT, it is not iterable"},
+		{"def f(a) { a }; f(*null)", "cannot spread NULL, it is not iterable"},
+		{"[1].size(*1.5)", "cannot spread FLOAT, it is not iterable"},
+		{`{**[1]}`, "cannot spread ARRAY into a hash, it is not a HASH"},
+		{"[*(1 + true)]", "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			switch obj := evaluated.(type) {
+			case *object.Array:
+				if obj.Inspect() != expected {
+					t.Errorf("%q: wrong array. want=%s, got=%s", tt.input, expected, obj.Inspect())
+				}
+			case *object.String:
+				testStringObject(t, obj, expected)
+			case *object.Error:
+				if obj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, obj.Message)
+				}
+			default:
+				t.Errorf("%q: unexpected result %T(%+v)", tt.input, evaluated, evaluated)
+			}
+		}
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "def(x) { x + 2; };"
 
@@ -276,6 +835,8 @@ func TestFunctionApplication(t *testing.T) {
 		{"add = def(x, y) { x + y; }; add(5, 5);", 10},
 		{"add = def(x, y) { x + y; }; add(5 + 5, add(5, 5));", 20},
 		{"def(x) { x; }(5)", 5},
+		{"x = 1; f = def(x) { x = 5; x }; f(2) * 10 + x", 51},
+		{"y = 1; f = def(x) { y = x }; f(2); y", 2},
 	}
 
 	for _, tt := range tests {
@@ -478,11 +1039,13 @@ func TestHashLiterals(t *testing.T) {
 	input := `two = "two";
 	{
 		"one": 10 - 9,
-		two: 1 + 1,
+		(two): 1 + 1,
 		"thr" + "ee": 6 / 2,
 		4: 4,
 		true: 5,
-		false: 6
+		false: 6,
+		seven: 7,
+		:eight: 8
 	}`
 
 	evaluated := testEval(input)
@@ -491,27 +1054,33 @@ func TestHashLiterals(t *testing.T) {
 		t.Fatalf("Eval did not return Hash. got=%T (%+v)", evaluated, evaluated)
 	}
 
-	expected := map[object.HashKey]int64{
-		object.NewString("one").HashKey():   1,
-		object.NewString("two").HashKey():   2,
-		object.NewString("three").HashKey(): 3,
-		object.NewInteger(4).HashKey():      4,
-		object.TRUE.HashKey():               5,
-		object.FALSE.HashKey():              6,
+	expected := []struct {
+		key   object.Hashable
+		value int64
+	}{
+		{object.NewString("one"), 1},
+		{object.NewString("two"), 2},
+		{object.NewString("three"), 3},
+		{object.NewInteger(4), 4},
+		{object.TRUE, 5},
+		{object.FALSE, 6},
+		{object.NewSymbol("seven"), 7},
+		{object.NewSymbol("eight"), 8},
 	}
 
-	if len(result.Pairs) != len(expected) {
-		t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+	if result.Len() != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", result.Len())
 	}
 
-	for expectedKey, expectedValue := range expected {
-		pair, ok := result.Pairs[expectedKey]
+	for _, tt := range expected {
+		value, ok := result.Get(tt.key)
 
 		if !ok {
-			t.Errorf("no pair for given key in Pairs")
+			t.Errorf("no pair for given key %s", tt.key.Inspect())
+			continue
 		}
 
-		testIntegerObject(t, pair.Value, expectedValue)
+		testIntegerObject(t, value, tt.value)
 	}
 }
 
@@ -565,6 +1134,52 @@ func TestHashIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestColonBeforeVariable(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a = [1, 2, 3]; n = 2; a[:n]", "[1, 2]"},
+		{"a = [1, 2, 3]; n = 2; a[0 :n]", "[1, 2]"},
+		{"n = 2; true ? 1 :n", "1"},
+		{"n = 2; false ? 1 :n", "2"},
+		{"h = {name: 1}; h[(:name)]", "1"},
+		{`b = 2; {"a" :b}`, `{"a": 2}`},
+		{`b = 2; {"a" : b}`, `{"a": 2}`},
+		{"k = 2; {k : 1, (k): 3}", "{2: 3, k: 1}"},
+	}
+
+	for _, tt := range tests {
+		if actual := testEval(tt.input).Inspect(); actual != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestSetInfixExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"set([1, 2]) | set([2, 3])", "set([1, 2, 3])"},
+		{"set([1, 2]) & set([2, 3])", "set([2])"},
+		{"set([1, 2]) - set([2, 3])", "set([1])"},
+		{"set([3, 1]) | set([2]) - set([1])", "set([3, 1, 2])"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		set, ok := evaluated.(*object.Set)
+		if !ok {
+			t.Errorf("object is not Set. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if set.Inspect() != tt.expected {
+			t.Errorf("wrong set. expected=%q, got=%q", tt.expected, set.Inspect())
+		}
+	}
+}
+
 func TestNamedFunctionStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -597,6 +1212,14 @@ func TestImportExpression(t *testing.T) {
 			`import("../fixtures/module"); module.a`,
 			nil,
 		},
+		{
+			`import("../fixtures/module"); s = module.Sum; s(1, 2)`,
+			3,
+		},
+		{
+			`import("../fixtures/module"); m = module; m.Sum(m.A, 1)`,
+			6,
+		},
 	}
 
 	for _, tt := range tests {
@@ -635,6 +1258,237 @@ func TestImportSearchPaths(t *testing.T) {
 	}
 }
 
+func TestFsModule(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fs.mkdir_p(path.join(d, "a", "b"))`, true},
+		{`fs.exists?(path.join(d, "a", "b"))`, true},
+		{`fs.exists?(path.join(d, "nope"))`, false},
+		{`f = open(path.join(d, "a", "x.txt"), "w"); f.write("hello"); f.close(); fs.stat(path.join(d, "a", "x.txt"))["size"]`, 5},
+		{`fs.stat(path.join(d, "a"))["dir?"]`, true},
+		{`fs.list_dir(path.join(d, "a")).size()`, 2},
+		{`fs.copy(path.join(d, "a", "x.txt"), path.join(d, "y.txt")); fs.glob(path.join(d, "*.txt")).size()`, 1},
+		{`fs.rename(path.join(d, "y.txt"), path.join(d, "z.txt")); fs.exists?(path.join(d, "z.txt"))`, true},
+		{`r = []; fs.walk(path.join(d, "a"), def(p) { r.yoink(path.basename(p)) }); r.size()`, 3},
+		{`fs.walk(d, def(p) { 1 + "a" })`, "type mismatch: INTEGER + STRING"},
+		{`fs.remove(path.join(d, "a")); fs.exists?(path.join(d, "a"))`, false},
+		{`fs.copy(d, path.join(d, "copy"))`, "fs.copy: " + filepath.Base(dir) + " is a directory"},
+		{`fs.stat(1)`, "argument 1 to `fs.stat` must be STRING, got=INTEGER"},
+		{`fs.walk(d)`, "wrong number of arguments to `fs.walk`. got=1, want=2"},
+		{`fs.walk(d, 1)`, "argument 2 to `fs.walk` must be FUNCTION|BUILTIN, got=INTEGER"},
+		{`fs.unknown()`, "undefined method `.unknown()` for MODULE"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(fmt.Sprintf("d = %q; %s", dir, tt.input))
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestFsWalkStopsAtNestedError(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a/1.txt", "a/2.txt", "b/3.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var visited []string
+	record := object.NewBuiltin("record", func(args ...object.Object) object.Object {
+		name := filepath.Base(args[0].(*object.String).Value)
+		visited = append(visited, name)
+		if name == "1.txt" {
+			return object.NewErrorFormat("failed at %s", name)
+		}
+		return object.NULL
+	})
+
+	l := lexer.New(fmt.Sprintf("fs.walk(%q, record)", dir))
+	p := parser.New(l, nil)
+	program, _ := p.ParseProgram()
+	env := object.NewEnvironment()
+	env.Set("record", record)
+
+	evaluated := Eval(program, env)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "failed at 1.txt" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+
+	expected := []string{filepath.Base(dir), "a", "1.txt"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("walk did not stop at the error. visited=%v, want=%v", visited, expected)
+	}
+}
+
+func TestPathModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`path.join("a", "b", "c.rl")`, filepath.Join("a", "b", "c.rl")},
+		{`path.dirname("a/b/c.rl")`, "a/b"},
+		{`path.basename("a/b/c.rl")`, "c.rl"},
+		{`path.ext("a/b/c.rl")`, ".rl"},
+		{`path.abs("/a/../b")`, "/b"},
+	}
+
+	for _, tt := range tests {
+		testStringObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCollectionsModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`r = []; foreach v in collections.heap().push(2).push(1) { r.yoink(v) }; r`, "[1, 2]"},
+		{`r = []; foreach i, v in collections.deque([1]).push_front(0) { r.yoink([i, v]) }; r`, "[[0, 0], [1, 1]]"},
+		{`r = []; foreach k, v in collections.counter("abb") { r.yoink([k, v]) }; r`, `[["b", 2], ["a", 1]]`},
+		{`r = []; foreach k in collections.counter("abb") { r.yoink(k) }; r`, `["b", "a"]`},
+		{`d = collections.default_hash(0); d["a"] += 1; r = []; foreach k, v in d { r.yoink([k, v]) }; r`, `[["a", 1]]`},
+		{`[x * 2 foreach x in collections.deque([1, 2])]`, "[2, 4]"},
+		{`[k foreach k, v in collections.counter("abb") if v == 1]`, `["a"]`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestProcessBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`exec(["echo", "hello"])["stdout"]`, "hello\n"},
+		{`exec(["sh", "-c", "echo err >&2"])["stderr"]`, "err\n"},
+		{`exec(["sh", "-c", "exit 3"])["exit_code"]`, 3},
+		{`exec(["cat"], {"stdin": "piped"})["stdout"]`, "piped"},
+		{`exec(["pwd"], {"cwd": "/"})["stdout"]`, "/\n"},
+		{`exec(["sh", "-c", "echo $A$B"], {"env": {"A": "x", "B": 1}})["stdout"]`, "x1\n"},
+		{`ENV["ROCKET_TEST"] = "set"; exec(["sh", "-c", "echo $ROCKET_TEST"])["stdout"]`, "set\n"},
+		{`ENV["ROCKET_TEST"] = "set"; ENV["ROCKET_TEST"]`, "set"},
+		{`ARGV.size()`, 0},
+		{`system(["true"])`, 0},
+		{`system(["false"])`, 1},
+		{`exec()`, "wrong number of arguments to `exec`. got=0, want=1"},
+		{`exec("ls")`, "argument 1 to `exec` must be ARRAY, got=STRING"},
+		{`exec([])`, "command given to `exec` must not be empty"},
+		{`exec(["echo", 1])`, "command given to `exec` must only contain STRING, got=INTEGER"},
+		{`exec(["echo"], {"nope": 1})`, "unknown option `nope` for `exec`"},
+		{`exec(["echo"], {"cwd": 1})`, "option cwd of `exec` must be STRING, got=INTEGER"},
+		{`spawn(["echo"], {"env": 1})`, "option env of `spawn` must be HASH, got=INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if errObj, ok := evaluated.(*object.Error); ok {
+				if errObj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+				}
+				continue
+			}
+			testStringObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`def gen() { yield 1; yield 2 }; r = 0; foreach i in gen() { r = r * 10 + i }; r`, 12},
+		{`def gen() { yield 1; yield 2 }; g = gen(); g.next(); g.next()`, 2},
+		{`def gen() { yield 1; return 5; yield 2 }; gen().to_a().size()`, 1},
+		{`def gen(n) { i = 0; while (i < n) yield i; i = i + 1 end }; gen(4).to_a().size()`, 4},
+		{`def nat() { i = 0; while (true) yield i; i = i + 1 end }; nat().map(def(x) { return x * x }).take(5).to_a()[4]`, 16},
+		{`def nat() { i = 0; while (true) yield i; i = i + 1 end }; def f() { foreach i in nat() { if (i == 3) return i end } }; f()`, 3},
+		{`def gen() { yield 1; yield 1 + "a" }; foreach i in gen() { puts(i) }`, "type mismatch: INTEGER + STRING"},
+		{`yield 1`, "yield outside of a generator function"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestGeneratorsDoNotLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	input := `
+	def nat() { i = 0; while (true) yield i; i = i + 1 end }
+	def first(n) { foreach i in nat() { if (i == n) return i end } }
+	runs = 0
+	while (runs < 50)
+	  first(3)
+	  nat().take(2).to_a()
+	  nat().zip(nat()).take(1).to_a()
+	  runs = runs + 1
+	end
+	`
+	testEval(input)
+
+	// stopped generators exit asynchronously
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("generators leaked goroutines. before=%d, after=%d", before, after)
+	}
+}
+
 func testNullObject(t *testing.T, obj object.Object) bool {
 	if obj != object.NULL {
 		t.Errorf("object is not object.NULL. got=%T (%+v)", obj, obj)
@@ -645,8 +1499,7 @@ func testNullObject(t *testing.T, obj object.Object) bool {
 
 func testEval(input string) object.Object {
 	l := lexer.New(input)
-	imports := make(map[string]struct{})
-	p := parser.New(l, imports)
+	p := parser.New(l, nil)
 	program, _ := p.ParseProgram()
 	env := object.NewEnvironment()
 
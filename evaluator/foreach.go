@@ -13,15 +13,27 @@ func evalForeach(fle *ast.Foreach, env *object.Environment) object.Object {
 		return object.NewErrorFormat("%s object doesn't implement the Iterable interface", val.Type())
 	}
 
-	child := object.NewEnclosedEnvironment(env)
+	child := object.NewScopeEnvironment(env, fle.Locals)
 
-	helper.Reset()
+	// leaving the loop early stops generators feeding the iterator
+	iterator := helper.Iter()
+	defer iterator.Close()
 
-	ret, idx, ok := helper.Next()
+	ret, idx, ok := iterator.Next()
 
 	for ok {
+		if object.IsError(ret) {
+			return ret
+		}
+		ret, idx = foreachItem(fle, val, ret, idx)
 
-		child.Set(fle.Ident, ret)
+		if fle.Pattern != nil {
+			if err := destructure(fle.Pattern, ret, child); err != nil {
+				return err
+			}
+		} else {
+			child.Set(fle.Ident, ret)
+		}
 
 		idxName := fle.Index
 		if idxName != "" {
@@ -37,8 +49,27 @@ func evalForeach(fle *ast.Foreach, env *object.Environment) object.Object {
 			return rt
 		}
 
-		ret, idx, ok = helper.Next()
+		// the next iteration continues with a copy of the variables if a
+		// function was created in the loop, so it keeps the values of its
+		// iteration
+		if child.Captured() {
+			child = child.Copy()
+		}
+		ret, idx, ok = iterator.Next()
 	}
 
 	return val
 }
+
+// foreachItem returns the value and index of an iteration. A hash iterates
+// over its keys, but with an index the key is the index and its value is the
+// value. Counters and default hashes iterate like hashes.
+func foreachItem(fle *ast.Foreach, iterable, value, idx object.Object) (object.Object, object.Object) {
+	switch iterable.(type) {
+	case *object.Hash, *object.Counter, *object.DefaultHash:
+		if fle.Index != "" {
+			return idx, value
+		}
+	}
+	return value, idx
+}
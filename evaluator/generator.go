@@ -0,0 +1,112 @@
+package evaluator
+
+import (
+	"runtime"
+
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/object"
+)
+
+// errGeneratorClosed unwinds the body of a generator whose iterator was
+// closed while the generator waited in a yield.
+var errGeneratorClosed = object.NewError("generator closed")
+
+// generator runs the body of a generator function in its own goroutine. The
+// goroutine and the caller take turns: the goroutine only runs between a
+// call of next and the following yield, so the environment is never used
+// concurrently.
+type generator struct {
+	values chan object.Object
+	resume chan struct{}
+	stop   chan struct{}
+
+	started bool
+	calls   *callStack
+}
+
+// newGenerator returns an iterator over all values yielded by body. The body
+// is started on the first call of Next and stopped when the iterator is
+// closed, which happens at the latest when it is garbage collected.
+func newGenerator(body *ast.Block, env *object.Environment) *object.Iterator {
+	g := &generator{
+		values: make(chan object.Object),
+		resume: make(chan struct{}),
+		stop:   make(chan struct{}),
+		calls:  &callStack{},
+	}
+	env.SetYield(g.yield)
+
+	var index int64
+	iterator := object.NewClosableIterator(func() (object.Object, object.Object, bool) {
+		// the body runs on the stack of its goroutine while the caller waits
+		caller := calls
+		calls = g.calls
+		defer func() { calls = caller }()
+
+		if !g.started {
+			g.started = true
+			go g.run(body, env)
+		} else {
+			g.resume <- struct{}{}
+		}
+
+		value, ok := <-g.values
+		if !ok {
+			return nil, nil, false
+		}
+
+		index++
+		return value, object.NewInteger(index - 1), true
+	}, func() {
+		close(g.stop)
+	})
+
+	// the goroutine does not reference the iterator, so an iterator that is
+	// not used anymore is collected and stops the goroutine
+	runtime.SetFinalizer(iterator, func(it *object.Iterator) { it.Close() })
+
+	return iterator
+}
+
+func (g *generator) run(body *ast.Block, env *object.Environment) {
+	defer close(g.values)
+
+	result := Eval(body, env)
+	if object.IsError(result) && result != errGeneratorClosed {
+		select {
+		case g.values <- result:
+		case <-g.stop:
+		}
+	}
+}
+
+// yield hands value to the consumer and waits until the next value is
+// requested.
+func (g *generator) yield(value object.Object) object.Object {
+	select {
+	case g.values <- value:
+	case <-g.stop:
+		return errGeneratorClosed
+	}
+
+	select {
+	case <-g.resume:
+		return object.NULL
+	case <-g.stop:
+		return errGeneratorClosed
+	}
+}
+
+func evalYield(node *ast.Yield, env *object.Environment) object.Object {
+	yield := env.Yield()
+	if yield == nil {
+		return object.NewErrorFormat("yield outside of a generator function")
+	}
+
+	value := Eval(node.Value, env)
+	if object.IsError(value) {
+		return value
+	}
+
+	return yield(value)
+}
@@ -6,10 +6,17 @@ import (
 )
 
 func evalHash(node *ast.Hash, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash(nil)
 
-	for keyNode, valueNode := range node.Pairs {
-		key := Eval(keyNode, env)
+	for _, pair := range node.Pairs {
+		if splat, ok := pair.Key.(*ast.Splat); ok {
+			if err := spreadHash(hash, splat, env); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := Eval(pair.Key, env)
 		if object.IsError(key) {
 			return key
 		}
@@ -19,14 +26,31 @@ func evalHash(node *ast.Hash, env *object.Environment) object.Object {
 			return object.NewErrorFormat("unusable as hash key: %s", key.Type())
 		}
 
-		value := Eval(valueNode, env)
+		value := Eval(pair.Value, env)
 		if object.IsError(value) {
 			return value
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey, value)
 	}
 
-	return object.NewHash(pairs)
+	return hash
+}
+
+// spreadHash copies all pairs of the hash splat evaluates to into hash.
+func spreadHash(hash *object.Hash, splat *ast.Splat, env *object.Environment) object.Object {
+	value := Eval(splat.Value, env)
+	if object.IsError(value) {
+		return value
+	}
+
+	other, ok := value.(*object.Hash)
+	if !ok {
+		return object.NewErrorFormat("cannot spread %s into a hash, it is not a HASH", value.Type())
+	}
+
+	for _, pair := range other.Pairs() {
+		hash.Set(pair.Key, pair.Value)
+	}
+	return nil
 }
@@ -7,13 +7,54 @@ import (
 )
 
 func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
+	if node.Resolved {
+		if node.Slot == -1 {
+			if val, ok := predefined(node.Value); ok {
+				return val
+			}
+		} else if val := env.GetAt(node.Depth, node.Slot); val != nil {
+			return val
+		}
+	}
+
 	if val, ok := env.Get(node.Value); ok {
 		return val
 	}
 
-	if builtin, ok := stdlib.Builtins[node.Value]; ok {
-		return builtin
+	if val, ok := predefined(node.Value); ok {
+		return val
 	}
 
 	return object.NewErrorFormat("identifier not found: " + node.Value)
 }
+
+// setIdentifier assigns val to the variable of node.
+func setIdentifier(node *ast.Identifier, val object.Object, env *object.Environment) {
+	if node.Resolved && node.Slot != -1 && env.SetAt(node.Depth, node.Slot, val) {
+		return
+	}
+
+	env.Set(node.Value, val)
+}
+
+// predefined returns the builtin function, module or global called name.
+func predefined(name string) (object.Object, bool) {
+	if builtin, ok := stdlib.Builtins[name]; ok {
+		return builtin, true
+	}
+
+	if module, ok := stdlib.Modules[name]; ok {
+		return module, true
+	}
+
+	if global, ok := stdlib.Globals[name]; ok {
+		return global, true
+	}
+
+	return nil, false
+}
+
+func isPredefined(name string) bool {
+	_, ok := predefined(name)
+	return ok
+}
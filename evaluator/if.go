@@ -6,16 +6,35 @@ import (
 )
 
 func evalIf(ie *ast.If, env *object.Environment) object.Object {
-	condition := Eval(ie.Condition, env)
+	return evalConditional(ie.Condition, false, ie.Consequence, ie.Alternative, env)
+}
+
+func evalUnless(u *ast.Unless, env *object.Environment) object.Object {
+	return evalConditional(u.Condition, true, u.Consequence, u.Alternative, env)
+}
 
-	if object.IsError(condition) {
-		return condition
+// evalConditional evaluates consequence if the truthiness of condition
+// differs from negate and alternative otherwise.
+func evalConditional(condition ast.Expression, negate bool, consequence, alternative *ast.Block, env *object.Environment) object.Object {
+	ok, err := evalCondition(condition, negate, env)
+	if err != nil {
+		return err
 	}
-	if object.IsTruthy(condition) {
-		return Eval(ie.Consequence, env)
-	} else if ie.Alternative != nil {
-		return Eval(ie.Alternative, env)
+
+	if ok {
+		return Eval(consequence, env)
+	} else if alternative != nil {
+		return Eval(alternative, env)
 	} else {
 		return object.NULL
 	}
 }
+
+func evalCondition(condition ast.Expression, negate bool, env *object.Environment) (bool, object.Object) {
+	v := Eval(condition, env)
+	if object.IsError(v) {
+		return false, v
+	}
+
+	return object.IsTruthy(v) != negate, nil
+}
@@ -14,6 +14,12 @@ func evalIndex(left, index object.Object) object.Object {
 		return evalStringIndexExpression(left, index)
 	case left.Type() == object.MODULE_OBJ:
 		return evalModuleIndexExpression(left, index)
+	case left.Type() == object.DEQUE_OBJ && index.Type() == object.INTEGER_OBJ:
+		return left.(*object.Deque).Get(int(index.(*object.Integer).Value))
+	case left.Type() == object.COUNTER_OBJ:
+		return evalCounterIndexExpression(left, index)
+	case left.Type() == object.DEFAULT_HASH_OBJ:
+		return evalDefaultHashIndexExpression(left, index)
 	default:
 		return object.NewErrorFormat("index operator not supported: %s", left.Type())
 	}
@@ -96,12 +102,32 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 		return object.NewErrorFormat("unusable as hash key: %s", index.Type())
 	}
 
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	value, ok := hashObject.Get(key)
 	if !ok {
 		return object.NULL
 	}
 
-	return pair.Value
+	return value
+}
+
+// evalCounterIndexExpression returns the count of index, 0 if it was never
+// added.
+func evalCounterIndexExpression(counter, index object.Object) object.Object {
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return object.NewErrorFormat("unusable as counter key: %s", index.Type())
+	}
+	return object.NewInteger(counter.(*object.Counter).Count(key))
+}
+
+// evalDefaultHashIndexExpression returns the value of index, which is
+// created from the default if the key is missing.
+func evalDefaultHashIndexExpression(hash, index object.Object) object.Object {
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return object.NewErrorFormat("unusable as hash key: %s", index.Type())
+	}
+	return hash.(*object.DefaultHash).Get(key)
 }
 
 func evalArrayIndexExpression(array, index object.Object) object.Object {
@@ -33,33 +33,24 @@ func TestEvalIndex(t *testing.T) {
 
 		// {"a": 1}["a"] => 1
 		{
-			left: object.NewHash(map[object.HashKey]object.HashPair{
-				object.NewString("a").HashKey(): object.HashPair{
-					Key:   object.NewString("a"),
-					Value: object.NewInteger(1),
-				},
+			left: object.NewHash([]object.HashPair{
+				{Key: object.NewString("a"), Value: object.NewInteger(1)},
 			}),
 			index:    object.NewString("a"),
 			expected: object.NewInteger(1),
 		},
 		// {"a": 1}["b"] => NULL
 		{
-			left: object.NewHash(map[object.HashKey]object.HashPair{
-				object.NewString("a").HashKey(): object.HashPair{
-					Key:   object.NewString("a"),
-					Value: object.NewInteger(1),
-				},
+			left: object.NewHash([]object.HashPair{
+				{Key: object.NewString("a"), Value: object.NewInteger(1)},
 			}),
 			index:    object.NewString("b"),
 			expected: object.NULL,
 		},
 		// {"a": 1}[NULL] => ERROR: unusable as hash key: NULL
 		{
-			left: object.NewHash(map[object.HashKey]object.HashPair{
-				object.NewString("a").HashKey(): object.HashPair{
-					Key:   object.NewString("a"),
-					Value: object.NewInteger(1),
-				},
+			left: object.NewHash([]object.HashPair{
+				{Key: object.NewString("a"), Value: object.NewInteger(1)},
 			}),
 			index:    object.NULL,
 			expected: object.NewErrorFormat("unusable as hash key: NULL"),
@@ -1,8 +1,10 @@
 package evaluator
 
 import (
+	"math"
 	"strings"
 
+	"github.com/flipez/rocket-lang/ast"
 	"github.com/flipez/rocket-lang/object"
 )
 
@@ -27,6 +29,27 @@ func evalIntegerInfix(operator string, left, right object.Object) object.Object
 			return object.NewErrorFormat("division by zero not allowed")
 		}
 		return object.NewInteger(leftVal % rightVal)
+	case "**":
+		if rightVal < 0 {
+			return object.NewFloat(math.Pow(float64(leftVal), float64(rightVal)))
+		}
+		return object.NewInteger(integerPower(leftVal, rightVal))
+	case "&":
+		return object.NewInteger(leftVal & rightVal)
+	case "|":
+		return object.NewInteger(leftVal | rightVal)
+	case "^":
+		return object.NewInteger(leftVal ^ rightVal)
+	case "<<":
+		if rightVal < 0 {
+			return object.NewErrorFormat("negative shift amount not allowed")
+		}
+		return object.NewInteger(leftVal << uint64(rightVal))
+	case ">>":
+		if rightVal < 0 {
+			return object.NewErrorFormat("negative shift amount not allowed")
+		}
+		return object.NewInteger(leftVal >> uint64(rightVal))
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case "<=":
@@ -40,6 +63,20 @@ func evalIntegerInfix(operator string, left, right object.Object) object.Object
 	}
 }
 
+// integerPower returns base ** exponent for a non-negative exponent by
+// squaring, overflows wrap around like for multiplication.
+func integerPower(base, exponent int64) int64 {
+	result := int64(1)
+	for exponent > 0 {
+		if exponent&1 == 1 {
+			result *= base
+		}
+		base *= base
+		exponent >>= 1
+	}
+	return result
+}
+
 func evalFloatInfix(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.Float).Value
 	rightVal := right.(*object.Float).Value
@@ -56,6 +93,8 @@ func evalFloatInfix(operator string, left, right object.Object) object.Object {
 			return object.NewErrorFormat("division by zero not allowed")
 		}
 		return object.NewFloat(leftVal / rightVal)
+	case "**":
+		return object.NewFloat(math.Pow(leftVal, rightVal))
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case "<=":
@@ -69,12 +108,36 @@ func evalFloatInfix(operator string, left, right object.Object) object.Object {
 	}
 }
 
+// evalLogicalInfixExpression evaluates `&&` and `||`. The right side is only
+// evaluated if the left side does not already determine the result.
+func evalLogicalInfixExpression(node *ast.Infix, left object.Object, env *object.Environment) object.Object {
+	if node.Operator == "&&" && object.IsFalsy(left) {
+		return object.FALSE
+	}
+	if node.Operator == "||" && object.IsTruthy(left) {
+		return object.TRUE
+	}
+
+	right := Eval(node.Right, env)
+	if object.IsError(right) {
+		return right
+	}
+
+	return nativeBoolToBooleanObject(object.IsTruthy(right))
+}
+
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
 	case operator == "==":
 		return nativeBoolToBooleanObject(object.CompareObjects(left, right))
 	case operator == "!=":
 		return nativeBoolToBooleanObject(!object.CompareObjects(left, right))
+	case operator == "=~":
+		return evalMatchInfixExpression(left, right)
+	case operator == ">>" && object.IsCallable(left) && object.IsCallable(right):
+		return object.NewComposedFunction(left, right)
+	case operator == "<<" && object.IsCallable(left) && object.IsCallable(right):
+		return object.NewComposedFunction(right, left)
 	case object.IsNumber(left) && object.IsNumber(right):
 		if left.Type() == right.Type() && operator != "/" {
 			if left.Type() == object.INTEGER_OBJ {
@@ -116,11 +179,33 @@ func evalInfixExpression(operator string, left, right object.Object) object.Obje
 		return evalStringInfixExpression(operator, left, right)
 	case left.Type() == object.ARRAY_OBJ && right.Type() == object.ARRAY_OBJ:
 		return evalArrayInfixExpression(operator, left, right)
+	case left.Type() == object.SET_OBJ && right.Type() == object.SET_OBJ:
+		return evalSetInfixExpression(operator, left, right)
 	default:
 		return object.NewErrorFormat("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
+// evalMatchInfixExpression matches a STRING against a REGEX and returns the
+// position of the first match or NULL. The operands can be given in any order.
+func evalMatchInfixExpression(left, right object.Object) object.Object {
+	str, strOk := left.(*object.String)
+	re, reOk := right.(*object.Regex)
+	if !strOk && !reOk {
+		str, strOk = right.(*object.String)
+		re, reOk = left.(*object.Regex)
+	}
+	if !strOk || !reOk {
+		return object.NewErrorFormat("unknown operator: %s =~ %s", left.Type(), right.Type())
+	}
+
+	loc := re.Regexp.FindStringIndex(str.Value)
+	if loc == nil {
+		return object.NULL
+	}
+	return object.NewInteger(int64(loc[0]))
+}
+
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
@@ -148,3 +233,19 @@ func evalArrayInfixExpression(operator string, left, right object.Object) object
 		return object.NewErrorFormat("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
+
+func evalSetInfixExpression(operator string, left, right object.Object) object.Object {
+	leftSet := left.(*object.Set)
+	rightSet := right.(*object.Set)
+
+	switch operator {
+	case "|":
+		return leftSet.Union(rightSet)
+	case "&":
+		return leftSet.Intersection(rightSet)
+	case "-":
+		return leftSet.Difference(rightSet)
+	default:
+		return object.NewErrorFormat("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
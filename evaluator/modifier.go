@@ -0,0 +1,23 @@
+package evaluator
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/object"
+	"github.com/flipez/rocket-lang/token"
+)
+
+func evalModifier(m *ast.Modifier, env *object.Environment) object.Object {
+	switch m.Token.Type {
+	case token.WHILE, token.UNTIL:
+		return evalLoop(m.Condition, m.Token.Type == token.UNTIL, m.Statement, m.Locals, env)
+	default:
+		ok, err := evalCondition(m.Condition, m.Token.Type == token.UNLESS, env)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return object.NULL
+		}
+		return Eval(m.Statement, env)
+	}
+}
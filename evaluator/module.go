@@ -23,8 +23,7 @@ func EvalModule(name string) object.Object {
 	}
 
 	l := lexer.New(string(b))
-	imports := make(map[string]struct{})
-	p := parser.New(l, imports)
+	p := parser.New(l, nil)
 
 	module, _ := p.ParseProgram()
 
@@ -5,15 +5,56 @@ import (
 	"github.com/flipez/rocket-lang/object"
 )
 
-func evalObjectCall(call *ast.ObjectCall, env *object.Environment) object.Object {
+// evalObjectCall evaluates `obj.name(args)` and `obj.name`. A built-in
+// method of obj always wins. Otherwise the member called name of a hash or
+// module is used: it is called if arguments are given and returned as is
+// without parentheses. Missing members are null. The leading arguments are
+// passed in front of the given ones.
+func evalObjectCall(call *ast.ObjectCall, env *object.Environment, leading ...object.Object) object.Object {
 	obj := Eval(call.Object, env)
-	if method, ok := call.Call.(*ast.Call); ok {
-		args := evalExpressions(call.Call.(*ast.Call).Arguments, env)
-		ret := obj.InvokeMethod(method.Callable.String(), *env, args...)
-		if ret != nil {
+	if call.Safe && obj == object.NULL {
+		return object.NULL
+	}
+
+	switch c := call.Call.(type) {
+	case *ast.Call:
+		name := c.Callable.String()
+		args := evalExpressions(c.Arguments, env)
+		if len(args) == 1 && object.IsError(args[0]) {
+			return args[0]
+		}
+		args = append(leading, args...)
+		if ret := obj.InvokeMethod(name, *env, args...); ret != nil {
+			return ret
+		}
+
+		if member, ok := objectMember(obj, name); ok {
+			switch member.(type) {
+			case *object.Function, *object.Builtin:
+				return callFunction(call.Token, call, member, args)
+			}
+		}
+		return object.NewErrorFormat("undefined method `.%s()` for %s", name, obj.Type())
+	case *ast.Identifier:
+		if ret := obj.InvokeMethod(c.Value, *env); ret != nil {
 			return ret
 		}
+
+		if _, ok := obj.(object.MemberHolder); ok {
+			if member, ok := objectMember(obj, c.Value); ok {
+				return member
+			}
+			return object.NULL
+		}
+		return object.NewErrorFormat("undefined method `.%s` for %s", c.Value, obj.Type())
 	}
 
-	return object.NewErrorFormat("undefined method `.%s()` for %s", call.Call.(*ast.Call).Callable.String(), obj.Type())
+	return object.NewErrorFormat("invalid method call `%s`", call.Call)
+}
+
+func objectMember(obj object.Object, name string) (object.Object, bool) {
+	if holder, ok := obj.(object.MemberHolder); ok {
+		return holder.Member(name)
+	}
+	return nil, false
 }
@@ -0,0 +1,35 @@
+package evaluator
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/object"
+)
+
+// evalPipe evaluates `value |> right`. A call on the right side gets value
+// as its first argument, anything else has to evaluate to a callable which
+// is called with value.
+func evalPipe(value object.Object, right ast.Expression, env *object.Environment) object.Object {
+	switch right := right.(type) {
+	case *ast.Call:
+		function := Eval(right.Callable, env)
+		if object.IsError(function) {
+			return function
+		}
+		args := evalExpressions(right.Arguments, env)
+		if len(args) == 1 && object.IsError(args[0]) {
+			return args[0]
+		}
+
+		return callFunction(right.Token, right, function, append([]object.Object{value}, args...))
+	case *ast.ObjectCall:
+		if _, ok := right.Call.(*ast.Call); ok {
+			return evalObjectCall(right, env, value)
+		}
+	}
+
+	function := Eval(right, env)
+	if object.IsError(function) {
+		return function
+	}
+	return applyFunction(function, []object.Object{value})
+}
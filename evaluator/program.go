@@ -8,6 +8,7 @@ import (
 func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
 
+	env.Declare(program.Locals)
 	for _, statement := range program.Statements {
 		result = Eval(statement, env)
 
@@ -6,16 +6,33 @@ import (
 )
 
 func evalWhile(w *ast.While, env *object.Environment) object.Object {
-	child := object.NewEnclosedEnvironment(env)
+	return evalLoop(w.Condition, false, w.Body, w.Locals, env)
+}
+
+func evalUntil(u *ast.Until, env *object.Environment) object.Object {
+	return evalLoop(u.Condition, true, u.Body, u.Locals, env)
+}
+
+// evalLoop evaluates body as long as the truthiness of condition differs
+// from negate. Without condition it only stops on return or an error. The
+// loop has its own scope with the variables locals.
+func evalLoop(condition ast.Expression, negate bool, body ast.Node, locals *ast.Locals, env *object.Environment) object.Object {
+	child := object.NewScopeEnvironment(env, locals)
 
-	v := Eval(w.Condition, child)
-	for object.IsTruthy(v) {
-		rt := Eval(w.Body, child)
+	for {
+		if condition != nil {
+			ok, err := evalCondition(condition, negate, child)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return object.NULL
+			}
+		}
+
+		rt := Eval(body, child)
 		if rt != nil && (rt.Type() == object.RETURN_VALUE_OBJ || rt.Type() == object.ERROR_OBJ) {
 			return rt
 		}
-		v = Eval(w.Condition, env)
 	}
-
-	return object.NULL
 }
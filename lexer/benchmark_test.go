@@ -0,0 +1,30 @@
+package lexer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/flipez/rocket-lang/token"
+)
+
+func BenchmarkNextToken(b *testing.B) {
+	var input strings.Builder
+	for _, file := range []string{"../tests/while.rl", "../tests/indexables.rl"} {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			b.Fatal(err)
+		}
+		input.Write(source)
+		input.WriteString("\n")
+	}
+	source := strings.Repeat(input.String(), 20)
+
+	b.SetBytes(int64(len(source)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := New(source)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+	}
+}
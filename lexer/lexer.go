@@ -2,317 +2,396 @@ package lexer
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/flipez/rocket-lang/token"
 )
 
+// Lexer splits a program into tokens. It reads the input rune by rune and
+// keeps the line and column of the current one, every token records the
+// span between its first rune and the one after it.
 type Lexer struct {
-	input          string
-	position       int  // current position in input (points to current char)
-	readPosition   int  // current reading position in input (after current char)
-	ch             byte // current char under examination
-	currentLine    int
-	positionInLine int
+	input        string
+	position     int  // byte offset of the current char in input
+	readPosition int  // byte offset after the current char
+	ch           rune // current char under examination, 0 at the end
+	line         int  // line of the current char, starting at 0
+	column       int  // column of the current char in runes, starting at 1
+
+	// the bodies of heredocs start on the line after their marker, they are
+	// skipped once the lexer reaches the end of that line
+	heredocEnd   int // offset after the bodies, 0 if there are none
+	heredocLines int // lines of the bodies
+}
+
+// operators maps the operators and delimiters to their token types.
+var operators = map[string]token.TokenType{
+	"=":  token.ASSIGN,
+	"==": token.EQ,
+	"=~": token.MATCH,
+	"!":  token.BANG,
+	"!=": token.NOT_EQ,
+	"+":  token.PLUS,
+	"+=": token.PLUS_ASSIGN,
+	"-":  token.MINUS,
+	"-=": token.MINUS_ASSIGN,
+	"->": token.ARROW,
+	"*":  token.ASTERISK,
+	"**": token.POWER,
+	"*=": token.ASTERISK_ASSIGN,
+	"/":  token.SLASH,
+	"/=": token.SLASH_ASSIGN,
+	"%":  token.PERCENT,
+	"%=": token.PERCENT_ASSIGN,
+	"&":  token.BIT_AND,
+	"&&": token.AND,
+	"&.": token.SAFE_PERIOD,
+	"&:": token.METHOD_REF,
+	"|":  token.BIT_OR,
+	"||": token.OR,
+	"|>": token.PIPE,
+	"^":  token.BIT_XOR,
+	"?":  token.QUESTION,
+	"??": token.NULL_COALESCE,
+	"<":  token.LT,
+	"<=": token.LT_EQ,
+	"<<": token.SHIFT_LEFT,
+	">":  token.GT,
+	">=": token.GT_EQ,
+	">>": token.SHIFT_RIGHT,
+	";":  token.SEMICOLON,
+	",":  token.COMMA,
+	".":  token.PERIOD,
+	":":  token.COLON,
+	"{":  token.LBRACE,
+	"}":  token.RBRACE,
+	"(":  token.LPAREN,
+	")":  token.RPAREN,
+	"[":  token.LBRACKET,
+	"]":  token.RBRACKET,
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input, currentLine: 0, positionInLine: 0}
+	l := &Lexer{input: input}
 	l.readChar()
 	return l
 }
 
 func (l *Lexer) readChar() {
-	if l.readPosition >= len(l.input) {
-		l.ch = 0
-	} else {
-		l.ch = l.input[l.readPosition]
+	if l.ch == '\n' {
+		l.line += 1
+		l.column = 0
+
+		if l.heredocEnd != 0 {
+			l.readPosition = l.heredocEnd
+			l.line += l.heredocLines
+			l.heredocEnd, l.heredocLines = 0, 0
+		}
 	}
+	l.column += 1
+
 	l.position = l.readPosition
-	l.readPosition += 1
-	l.positionInLine += 1
+	l.ch, l.readPosition = l.charAt(l.readPosition)
 }
 
-func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
+// charAt returns the char starting at offset and the offset after it.
+func (l *Lexer) charAt(offset int) (rune, int) {
+	if offset >= len(l.input) {
+		return 0, len(l.input)
+	}
+	if ch := l.input[offset]; ch < utf8.RuneSelf {
+		return rune(ch), offset + 1
+	}
+	ch, width := utf8.DecodeRuneInString(l.input[offset:])
+	return ch, offset + width
+}
+
+func (l *Lexer) peekChar() rune {
+	ch, _ := l.charAt(l.readPosition)
+	return ch
+}
+
+// peekSecondChar returns the char after the next one.
+func (l *Lexer) peekSecondChar() rune {
+	_, next := l.charAt(l.readPosition)
+	ch, _ := l.charAt(next)
+	return ch
+}
 
+func (l *Lexer) NextToken() token.Token {
 	l.skipWhitespace()
 
-	switch l.ch {
-	case '=':
-		if l.peekChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			literal := string(ch) + string(l.ch)
-			tok.Type = token.EQ
-			tok.Literal = literal
-		} else {
-			tok.Type = token.ASSIGN
-			tok.Literal = string(l.ch)
-		}
+	tok := token.Token{LineNumber: l.line, LinePosition: l.column}
 
-	case '!':
-		if l.peekChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			literal := string(ch) + string(l.ch)
-			tok.Type = token.NOT_EQ
-			tok.Literal = literal
-		} else {
-			tok.Type = token.BANG
-			tok.Literal = string(l.ch)
-		}
-	case '/':
-		if l.peekChar() == '/' {
-			l.skipComment()
-			return l.NextToken()
-		} else {
-			tok.Type = token.SLASH
-			tok.Literal = string(l.ch)
-		}
-	case '+':
-		tok.Type = token.PLUS
-		tok.Literal = string(l.ch)
-	case '-':
-		tok.Type = token.MINUS
-		tok.Literal = string(l.ch)
-	case '*':
-		tok.Type = token.ASTERISK
-		tok.Literal = string(l.ch)
-	case '%':
-		tok.Type = token.PERCENT
-		tok.Literal = string(l.ch)
-	case '?':
-		tok.Type = token.QUESTION
-		tok.Literal = string(l.ch)
-	case '<':
-		if l.peekChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			tok.Type = token.LT_EQ
-			tok.Literal = string(ch) + string(l.ch)
-		} else {
-			tok.Type = token.LT
-			tok.Literal = string(l.ch)
-		}
-	case '>':
-		if l.peekChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			tok.Type = token.GT_EQ
-			tok.Literal = string(ch) + string(l.ch)
-		} else {
-			tok.Type = token.GT
-			tok.Literal = string(l.ch)
-		}
-	case ';':
-		tok.Type = token.SEMICOLON
-		tok.Literal = string(l.ch)
-	case ',':
-		tok.Type = token.COMMA
-		tok.Literal = string(l.ch)
-	case '.':
-		tok.Type = token.PERIOD
-		tok.Literal = string(l.ch)
-	case ':':
-		tok.Type = token.COLON
-		tok.Literal = string(l.ch)
-	case '{':
-		tok.Type = token.LBRACE
-		tok.Literal = string(l.ch)
-	case '}':
-		tok.Type = token.RBRACE
-		tok.Literal = string(l.ch)
-	case '(':
-		tok.Type = token.LPAREN
-		tok.Literal = string(l.ch)
-	case ')':
-		tok.Type = token.RPAREN
-		tok.Literal = string(l.ch)
-	case '[':
-		tok.Type = token.LBRACKET
-		tok.Literal = string(l.ch)
-	case ']':
-		tok.Type = token.RBRACKET
-		tok.Literal = string(l.ch)
-	case 0:
-		tok.Literal = ""
+	switch {
+	case l.ch == 0:
 		tok.Type = token.EOF
-	case '"':
-		tok.Type = token.STRING
-		tok.Literal = l.readString()
+	case l.ch == '"' || l.ch == '\'':
+		tok.Type, tok.Literal = token.STRING, l.readString()
+	case l.ch == '<' && l.isHeredoc():
+		tok.Type, tok.Literal = token.STRING, l.readHeredoc()
+	case isDigit(l.ch):
+		tok.Type, tok.Literal = l.readNumber()
+	case l.ch == ':' && l.isSymbol():
+		start := l.position
+		l.readChar()
+		l.readName()
+		tok.Type, tok.Literal = token.SYMBOL, l.input[start:l.position]
+	case isLetter(l.ch):
+		tok.Type, tok.Literal = l.readIdentifier()
 	default:
-		if isLetter(l.ch) {
-			tok.Literal = l.readIdentifier()
-			tok.Type = token.LookupIdent(tok.Literal)
-			return tok
-		} else if isDigit(l.ch) {
-			tok.Literal = l.readNumber()
-			if strings.Contains(tok.Literal, ".") {
-				tok.Type = token.FLOAT
-			} else {
-				tok.Type = token.INT
-			}
-			return tok
-		} else if i := isEmoji(l.ch); i > 0 {
-			out := make([]byte, i)
+		tok.Type, tok.Literal = l.readOperator()
+	}
 
-			for i := 0; i < len(out); i++ {
-				out[i] = l.ch
-				l.readChar()
-			}
+	tok.EndLineNumber = l.line
+	tok.EndLinePosition = l.column
+	return tok
+}
 
-			tok.Literal = token.LookupLiteral(string(out))
-			tok.Type = token.LookupEmoji(string(out))
+// readOperator reads the longest operator starting at the current char,
+// or a single illegal char.
+func (l *Lexer) readOperator() (token.TokenType, string) {
+	start := l.position
 
-			return tok
-		} else {
-			tok.Type = token.ILLEGAL
-			tok.Literal = string(l.ch)
+	if l.readPosition < len(l.input) {
+		if tokenType, ok := operators[l.input[start:l.readPosition+1]]; ok {
+			l.readChar()
+			l.readChar()
+			return tokenType, l.input[start:l.position]
 		}
 	}
 
-	tok.LineNumber = l.currentLine
-	tok.LinePosition = l.positionInLine
+	tokenType, ok := operators[l.input[start:l.readPosition]]
+	if !ok {
+		tokenType = token.ILLEGAL
+	}
 	l.readChar()
-	return tok
+	return tokenType, l.input[start:l.position]
 }
 
+// readString reads a string in double, triple or single quotes and returns
+// it as written, the parser handles the escape sequences. Strings in
+// single quotes are raw, a backslash does not escape their closing quote.
 func (l *Lexer) readString() string {
-	position := l.position + 1
-	for {
-		l.readChar()
-		if l.ch == '"' || l.ch == 0 {
-			break
-		}
+	start := l.position
+	delimiter := string(l.ch)
+	if strings.HasPrefix(l.input[start:], `"""`) {
+		delimiter = `"""`
 	}
-	return l.input[position:l.position]
-}
-
-func (l *Lexer) readIdentifier() string {
-	id := ""
 
-	position := l.position
-	rposition := l.readPosition
-
-	for l.isIdentifier(l.ch) {
-		id += string(l.ch)
+	for i := 0; i < len(delimiter); i++ {
 		l.readChar()
 	}
 
-	if strings.Contains(id, ".") {
-		offset := strings.Index(id, ".")
-		id = id[:offset]
+	for l.ch != 0 && !strings.HasPrefix(l.input[l.position:], delimiter) {
+		if l.ch == '\\' && delimiter != "'" {
+			l.readChar()
+		}
+		l.readChar()
+	}
 
-		l.position = position
-		l.readPosition = rposition
-		for offset > 0 {
+	if l.ch != 0 {
+		for i := 0; i < len(delimiter); i++ {
 			l.readChar()
-			offset--
 		}
 	}
+	return l.input[start:l.position]
+}
 
-	return id
+// isHeredoc reports whether a heredoc marker like <<~EOS starts at the
+// current char.
+func (l *Lexer) isHeredoc() bool {
+	if !strings.HasPrefix(l.input[l.position:], "<<~") {
+		return false
+	}
+	ch, _ := l.charAt(l.position + 3)
+	return isLetter(ch)
 }
 
-func (l *Lexer) isNewline() bool {
-	if l.ch == '\n' {
-		l.currentLine += 1
-		l.positionInLine = 0
-		return true
+// readHeredoc reads the marker of a heredoc and its body, which starts on
+// the next line, or after the body of the heredoc before it on the same
+// line. The body ends with a line containing only the name of the marker.
+// It returns the marker, a newline and the body including the closing
+// line. The token only spans the marker.
+func (l *Lexer) readHeredoc() string {
+	start := l.position
+	l.readChar()
+	l.readChar()
+	l.readChar()
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+	marker := l.input[start:l.position]
+	name := marker[len("<<~"):]
+
+	body := l.heredocEnd
+	if body == 0 {
+		body = len(l.input)
+		if i := strings.IndexByte(l.input[l.position:], '\n'); i != -1 {
+			body = l.position + i + 1
+		}
 	}
 
-	return false
-}
+	end := body
+	for end < len(l.input) {
+		line := l.input[end:]
+		if i := strings.IndexByte(line, '\n'); i != -1 {
+			line = line[:i]
+		}
+		end += len(line)
 
-func (l *Lexer) isIdentifier(ch byte) bool {
-	return !isDigit(ch) && !l.isWhitespace(ch) && !isBrace(ch) && !isOperator(ch) && !isComparison(ch) && !isCompound(ch) && !isBrace(ch) && !isParen(ch) && !isBracket(ch) && !isEmpty(ch)
-}
+		if strings.TrimSpace(line) == name {
+			break
+		}
+		end++
+	}
+	if end > len(l.input) {
+		end = len(l.input)
+	}
 
-func (l *Lexer) isWhitespace(ch byte) bool {
-	return ch == ' ' || ch == '\t' || l.isNewline() || ch == '\r'
-}
+	next := end
+	if next < len(l.input) {
+		next++
+	}
+	l.heredocLines += strings.Count(l.input[body:next], "\n")
+	l.heredocEnd = next
 
-func isOperator(ch byte) bool {
-	return ch == '+' || ch == '%' || ch == '-' || ch == '/' || ch == '*'
+	return marker + "\n" + l.input[body:end]
 }
 
-func isComparison(ch byte) bool {
-	return ch == '=' || ch == '>' || ch == '<'
-}
+// readIdentifier reads an identifier or keyword. Identifiers consist of
+// letters of any script, underscores and emojis, and may end with a ? or !
+// which is not part of an operator. Known emojis are read as their tokens.
+func (l *Lexer) readIdentifier() (token.TokenType, string) {
+	literal := l.readName()
+	if literal[0] >= utf8.RuneSelf {
+		emoji := strings.TrimSuffix(literal, "\ufe0f")
+		if tokenType := token.LookupEmoji(emoji); tokenType != token.IDENT {
+			return tokenType, token.LookupLiteral(emoji)
+		}
+	}
 
-func isCompound(ch byte) bool {
-	return ch == ',' || ch == ':' || ch == '"' || ch == ';'
+	return token.LookupIdent(literal), literal
 }
 
-func isBrace(ch byte) bool {
-	return ch == '{' || ch == '}'
-}
+// readName reads the name of an identifier or symbol.
+func (l *Lexer) readName() string {
+	start := l.position
+	for isLetter(l.ch) || isLetterModifier(l.ch) {
+		l.readChar()
+	}
 
-func isBracket(ch byte) bool {
-	return ch == '[' || ch == ']'
+	if next := l.peekChar(); (l.ch == '?' || l.ch == '!') && next != '=' && next != '?' {
+		l.readChar()
+	}
+	return l.input[start:l.position]
 }
 
-// is parenthesis
-func isParen(ch byte) bool {
-	return ch == '(' || ch == ')'
+// isSymbol reports whether the current colon starts a symbol like :name.
+// A colon right after a value, like in {"a":b} or s[1:n], separates it
+// from the name instead, and so does one opening an index like s[:n]. The
+// parser splits the symbol again where a colon is expected, like in
+// {"a" :b}.
+func (l *Lexer) isSymbol() bool {
+	if !isLetter(l.peekChar()) {
+		return false
+	}
+	if l.position == 0 {
+		return true
+	}
+	if l.input[l.position-1] == '[' {
+		return l.position == 1 || !endsValue(l.input[l.position-2])
+	}
+	return !endsValue(l.input[l.position-1])
 }
 
-func isEmpty(ch byte) bool {
-	return ch == 0
+// endsValue reports whether a value like a name, number, string or
+// closing bracket may end with ch.
+func endsValue(ch byte) bool {
+	return ch >= utf8.RuneSelf || isLetter(rune(ch)) || isDigit(rune(ch)) || strings.ContainsRune(`)]}"'?!`, rune(ch))
 }
 
-func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.isNewline() || l.ch == '\r' {
+// readNumber reads an integer or a float. Integers may be written in hex,
+// binary or octal with a prefix of 0x, 0b or 0o, floats may have an
+// exponent. Underscores between the digits are read as well, the parser
+// reports misplaced ones.
+func (l *Lexer) readNumber() (token.TokenType, string) {
+	start := l.position
+
+	if l.ch == '0' && strings.ContainsRune("xXbBoO", l.peekChar()) {
+		l.readChar()
 		l.readChar()
+		for isHexDigit(l.ch) || l.ch == '_' {
+			l.readChar()
+		}
+		return token.INT, l.input[start:l.position]
 	}
-}
 
-func (l *Lexer) skipComment() {
-	for !l.isNewline() && l.ch != 0 {
+	tokenType := token.TokenType(token.INT)
+	l.readDigits()
+
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		tokenType = token.FLOAT
 		l.readChar()
+		l.readDigits()
 	}
-	l.skipWhitespace()
-}
-
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
-}
 
-func isEmoji(ch byte) int {
-	switch int(ch) {
-	case 240:
-		return 4
-	case 226:
-		return 3
+	if l.ch == 'e' || l.ch == 'E' {
+		next := l.peekChar()
+		if isDigit(next) || (next == '+' || next == '-') && isDigit(l.peekSecondChar()) {
+			tokenType = token.FLOAT
+			l.readChar()
+			l.readChar()
+			l.readDigits()
+		}
 	}
 
-	return -1
+	return tokenType, l.input[start:l.position]
 }
 
-func (l *Lexer) readNumber() string {
-	position := l.position
-	for isDigit(l.ch) {
+func (l *Lexer) readDigits() {
+	for isDigit(l.ch) || l.ch == '_' {
 		l.readChar()
 	}
+}
 
-	if l.ch == '.' && isDigit(l.peekChar()) {
-		l.readChar()
-		for isDigit(l.ch) {
+// skipWhitespace skips whitespace and comments, which run until the end of
+// the line.
+func (l *Lexer) skipWhitespace() {
+	for {
+		switch {
+		case l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r':
 			l.readChar()
+		case l.ch == '/' && l.peekChar() == '/':
+			for l.ch != '\n' && l.ch != 0 {
+				l.readChar()
+			}
+		default:
+			return
 		}
 	}
+}
+
+// isLetter reports whether ch may start an identifier, which are letters,
+// underscores and symbols like emojis.
+func isLetter(ch rune) bool {
+	if ch < utf8.RuneSelf {
+		return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+	}
+	return unicode.IsLetter(ch) || unicode.Is(unicode.So, ch)
+}
 
-	return l.input[position:l.position]
+// isLetterModifier reports whether ch changes the letter before it, like
+// accents, skin tones of emojis or the joiner combining two emojis.
+func isLetterModifier(ch rune) bool {
+	return ch >= utf8.RuneSelf && (unicode.IsMark(ch) || unicode.Is(unicode.Sk, ch) || ch == '\u200d')
 }
 
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
-func (l *Lexer) peekChar() byte {
-	if l.readPosition >= len(l.input) {
-		return 0
-	} else {
-		return l.input[l.readPosition]
-	}
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
 }
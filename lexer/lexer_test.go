@@ -33,6 +33,17 @@ func TestNextToken(t *testing.T) {
 
 	5 <= 10 >= 5;
 	4 % 3;
+	a += 1; a -= 1; a *= 2; a /= 2; a %= 2;
+	2 ** 3;
+	a&&b || c;
+	6 & 3 | 1 ^ 2;
+	1 << 2 >> 1;
+	a =~ b;
+	yield a;
+	elsif unless until loop
+	null nil a ?? b; a&.b
+	->(x) do
+	a |> &:b
 	`
 
 	tests := []struct {
@@ -108,8 +119,8 @@ func TestNextToken(t *testing.T) {
 		{token.NOT_EQ, "!="},
 		{token.INT, "9"},
 		{token.SEMICOLON, ";"},
-		{token.STRING, "foobar"},
-		{token.STRING, "foo bar"},
+		{token.STRING, `"foobar"`},
+		{token.STRING, `"foo bar"`},
 		{token.LBRACKET, "["},
 		{token.INT, "1"},
 		{token.COMMA, ","},
@@ -117,9 +128,9 @@ func TestNextToken(t *testing.T) {
 		{token.RBRACKET, "]"},
 		{token.SEMICOLON, ";"},
 		{token.LBRACE, "{"},
-		{token.STRING, "foo"},
+		{token.STRING, `"foo"`},
 		{token.COLON, ":"},
-		{token.STRING, "bar"},
+		{token.STRING, `"bar"`},
 		{token.RBRACE, "}"},
 		{token.INT, "5"},
 		{token.LT_EQ, "<="},
@@ -131,6 +142,79 @@ func TestNextToken(t *testing.T) {
 		{token.PERCENT, "%"},
 		{token.INT, "3"},
 		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.PERCENT_ASSIGN, "%="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.INT, "2"},
+		{token.POWER, "**"},
+		{token.INT, "3"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.AND, "&&"},
+		{token.IDENT, "b"},
+		{token.OR, "||"},
+		{token.IDENT, "c"},
+		{token.SEMICOLON, ";"},
+		{token.INT, "6"},
+		{token.BIT_AND, "&"},
+		{token.INT, "3"},
+		{token.BIT_OR, "|"},
+		{token.INT, "1"},
+		{token.BIT_XOR, "^"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.INT, "1"},
+		{token.SHIFT_LEFT, "<<"},
+		{token.INT, "2"},
+		{token.SHIFT_RIGHT, ">>"},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.MATCH, "=~"},
+		{token.IDENT, "b"},
+		{token.SEMICOLON, ";"},
+		{token.YIELD, "yield"},
+		{token.IDENT, "a"},
+		{token.SEMICOLON, ";"},
+		{token.ELSIF, "elsif"},
+		{token.UNLESS, "unless"},
+		{token.UNTIL, "until"},
+		{token.LOOP, "loop"},
+		{token.NULL, "null"},
+		{token.NULL, "nil"},
+		{token.IDENT, "a"},
+		{token.NULL_COALESCE, "??"},
+		{token.IDENT, "b"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.SAFE_PERIOD, "&."},
+		{token.IDENT, "b"},
+		{token.ARROW, "->"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.DO, "do"},
+		{token.IDENT, "a"},
+		{token.PIPE, "|>"},
+		{token.METHOD_REF, "&:"},
+		{token.IDENT, "b"},
 		{token.EOF, ""},
 	}
 
@@ -150,3 +234,201 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	input := `größe = 1; 名前 done? a!=b 🔥 👍 ➕️ café`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "größe"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "名前"},
+		{token.IDENT, "done?"},
+		{token.IDENT, "a"},
+		{token.NOT_EQ, "!="},
+		{token.IDENT, "b"},
+		{token.IDENT, "🔥"},
+		{token.TRUE, "true"},
+		{token.PLUS, "+"},
+		{token.IDENT, "café"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q, got=%q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{"42", token.INT, "42"},
+		{"1_000_000", token.INT, "1_000_000"},
+		{"0xFF", token.INT, "0xFF"},
+		{"0b1010", token.INT, "0b1010"},
+		{"0o755", token.INT, "0o755"},
+		{"1.5", token.FLOAT, "1.5"},
+		{"1.5e-3", token.FLOAT, "1.5e-3"},
+		{"2E+10", token.FLOAT, "2E+10"},
+		{"1e5", token.FLOAT, "1e5"},
+		{"1_000.000_1", token.FLOAT, "1_000.000_1"},
+		{"1.to_s", token.INT, "1"},
+		{"1e", token.INT, "1"},
+		{"1e-a", token.INT, "1"},
+	}
+
+	for _, tt := range tests {
+		tok := New(tt.input).NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Errorf("%q: expected=%q %q, got=%q %q",
+				tt.input, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	input := "größe = \"a\nb\" // comment\n  x <= 0xFF"
+
+	tests := []struct {
+		expectedLiteral string
+		line, column    int
+		endLine, endCol int
+	}{
+		{"größe", 0, 1, 0, 6},
+		{"=", 0, 7, 0, 8},
+		{"\"a\nb\"", 0, 9, 1, 3},
+		{"x", 2, 3, 2, 4},
+		{"<=", 2, 5, 2, 7},
+		{"0xFF", 2, 8, 2, 12},
+		{"", 2, 12, 2, 12},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		got := [4]int{tok.LineNumber, tok.LinePosition, tok.EndLineNumber, tok.EndLinePosition}
+		expected := [4]int{tt.line, tt.column, tt.endLine, tt.endCol}
+		if got != expected {
+			t.Errorf("tests[%d] - span of %q wrong. expected=%v, got=%v", i, tok.Literal, expected, got)
+		}
+	}
+}
+
+func TestStrings(t *testing.T) {
+	input := `"a \" b" 'c \' """d " e""" puts(<<~EOS, <<~END) x
+  body
+  EOS
+ other
+ END
+y`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		line, column    int
+	}{
+		{token.STRING, `"a \" b"`, 0, 1},
+		{token.STRING, `'c \'`, 0, 10},
+		{token.STRING, `"""d " e"""`, 0, 16},
+		{token.IDENT, "puts", 0, 28},
+		{token.LPAREN, "(", 0, 32},
+		{token.STRING, "<<~EOS\n  body\n  EOS", 0, 33},
+		{token.COMMA, ",", 0, 39},
+		{token.STRING, "<<~END\n other\n END", 0, 41},
+		{token.RPAREN, ")", 0, 47},
+		{token.IDENT, "x", 0, 49},
+		{token.IDENT, "y", 5, 1},
+		{token.EOF, "", 5, 2},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q, got=%q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+
+		if tok.LineNumber != tt.line || tok.LinePosition != tt.column {
+			t.Errorf("tests[%d] - position of %q wrong. expected=%d:%d, got=%d:%d",
+				i, tok.Literal, tt.line, tt.column, tok.LineNumber, tok.LinePosition)
+		}
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	input := `[:a, :done?] {"k":v, name: :b} s[:n] s[1:n] c ? :x : y &:upcase`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LBRACKET, "["},
+		{token.SYMBOL, ":a"},
+		{token.COMMA, ","},
+		{token.SYMBOL, ":done?"},
+		{token.RBRACKET, "]"},
+		{token.LBRACE, "{"},
+		{token.STRING, `"k"`},
+		{token.COLON, ":"},
+		{token.IDENT, "v"},
+		{token.COMMA, ","},
+		{token.IDENT, "name"},
+		{token.COLON, ":"},
+		{token.SYMBOL, ":b"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "s"},
+		{token.LBRACKET, "["},
+		{token.COLON, ":"},
+		{token.IDENT, "n"},
+		{token.RBRACKET, "]"},
+		{token.IDENT, "s"},
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.COLON, ":"},
+		{token.IDENT, "n"},
+		{token.RBRACKET, "]"},
+		{token.IDENT, "c"},
+		{token.QUESTION, "?"},
+		{token.SYMBOL, ":x"},
+		{token.COLON, ":"},
+		{token.IDENT, "y"},
+		{token.METHOD_REF, "&:"},
+		{token.IDENT, "upcase"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q, got=%q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
@@ -10,13 +10,24 @@ import (
 	"github.com/flipez/rocket-lang/evaluator"
 	"github.com/flipez/rocket-lang/lexer"
 	"github.com/flipez/rocket-lang/object"
+	"github.com/flipez/rocket-lang/optimizer"
 	"github.com/flipez/rocket-lang/parser"
 	"github.com/flipez/rocket-lang/repl"
+	"github.com/flipez/rocket-lang/stdlib"
+)
+
+var (
+	optimize bool
+	dumpAST  bool
 )
 
 func main() {
 	version := flag.BoolP("version", "v", false, "Prints the version and build date.")
 	exec := flag.StringP("exec", "e", "", "Runs the given code.")
+	legacyOutput := flag.Bool("legacy-output", false, "Prints strings with puts and print in quotes like older versions.")
+	maxDepth := flag.Int("max-depth", evaluator.MaxCallDepth, "Maximum depth of nested function calls, tail calls don't count.")
+	flag.BoolVarP(&optimize, "optimize", "O", false, "Optimizes the program before running it.")
+	flag.BoolVar(&dumpAST, "dump-ast", false, "Prints the parsed program, optimized with -O, instead of running it.")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: rocket-lang [flags] [program file] [arguments]\n\nAvailable flags:\n")
@@ -24,23 +35,30 @@ func main() {
 		flag.PrintDefaults()
 	}
 
+	// everything after the program file belongs to the program
+	flag.CommandLine.SetInterspersed(false)
 	flag.Parse()
 
+	stdlib.LegacyOutput = *legacyOutput
+	evaluator.MaxCallDepth = *maxDepth
+
 	if *version {
 		print(repl.SplashVersion())
 		return
 	}
 
 	if len(*exec) > 0 {
+		stdlib.SetArguments(flag.Args())
 		runProgram(*exec)
 		return
 	}
 
-	if len(os.Args) == 1 {
+	if flag.NArg() == 0 {
 		repl.Start(os.Stdin, os.Stdout)
 	} else {
-		file, err := ioutil.ReadFile(os.Args[1])
+		file, err := ioutil.ReadFile(flag.Arg(0))
 		if err == nil {
+			stdlib.SetArguments(flag.Args()[1:])
 			runProgram(string(file))
 		}
 	}
@@ -49,7 +67,7 @@ func main() {
 func runProgram(input string) {
 	env := object.NewEnvironment()
 	l := lexer.New(input)
-	p := parser.New(l, make(map[string]struct{}))
+	p := parser.New(l, nil)
 
 	program, _ := p.ParseProgram()
 	if len(p.Errors()) > 0 {
@@ -57,6 +75,17 @@ func runProgram(input string) {
 		return
 	}
 
+	if optimize {
+		program = optimizer.Optimize(program)
+	}
+
+	if dumpAST {
+		for _, statement := range program.Statements {
+			fmt.Println(statement)
+		}
+		return
+	}
+
 	evaluated := evaluator.Eval(program, env)
 	if evaluated != nil {
 		fmt.Println(evaluated.Inspect())
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/flipez/rocket-lang/stdlib"
 )
 
 func TestRocketlangCode(t *testing.T) {
@@ -15,6 +17,13 @@ func TestRocketlangCode(t *testing.T) {
 		os.Stdout = origStdout
 	}()
 
+	// the expected output was written when puts still printed strings in
+	// quotes
+	stdlib.LegacyOutput = true
+	defer func() {
+		stdlib.LegacyOutput = false
+	}()
+
 	testDir := "tests"
 
 	matches, err := fs.Glob(os.DirFS(testDir), "*.rl")
@@ -42,19 +51,72 @@ func TestRocketlangCode(t *testing.T) {
 		}
 		defer os.Remove(fakeStdout.Name())
 
-		os.Stdout = fakeStdout
-		runProgram(string(rlCode))
+		// the optimized program has to print the same
+		for _, optimize = range []bool{false, true} {
+			if err := fakeStdout.Truncate(0); err != nil {
+				t.Errorf("%s: %s", match, err)
+				continue
+			}
+			if _, err := fakeStdout.Seek(0, 0); err != nil {
+				t.Errorf("%s: %s", match, err)
+				continue
+			}
+
+			os.Stdout = fakeStdout
+			runProgram(string(rlCode))
+			os.Stdout = origStdout
+
+			resultStdout, err := os.ReadFile(fakeStdout.Name())
+			if err != nil {
+				t.Errorf("%s: %s", match, err)
+				continue
+			}
+
+			if string(resultStdout) != string(expectedStdout) {
+				fmt.Printf("--- stdout ---\n%s--- expected ---\n%s", resultStdout, expectedStdout)
+				t.Errorf("%s: stdout does not match expected (optimized: %t)", match, optimize)
+			}
+		}
+		optimize = false
+	}
+}
+
+func TestOutputRepresentation(t *testing.T) {
+	tests := []struct {
+		input    string
+		legacy   bool
+		expected string
+	}{
+		{`puts("a", 1, ["b"])`, false, "a\n1\n[\"b\"]\n"},
+		{`print("a", 1); print("b")`, false, "a1b"},
+		{`puts("a")`, true, "\"a\"\n"},
+		{`print("a")`, true, "\"a\""},
+	}
+
+	origStdout := os.Stdout
+	defer func() {
 		os.Stdout = origStdout
+		stdlib.LegacyOutput = false
+	}()
 
-		resultStdout, err := os.ReadFile(fakeStdout.Name())
+	for _, tt := range tests {
+		fakeStdout, err := os.CreateTemp("", "output")
 		if err != nil {
-			t.Errorf("%s: %s", match, err)
-			continue
+			t.Fatal(err)
 		}
+		defer os.Remove(fakeStdout.Name())
+
+		stdlib.LegacyOutput = tt.legacy
+		os.Stdout = fakeStdout
+		runProgram(tt.input)
+		os.Stdout = origStdout
 
-		if string(resultStdout) != string(expectedStdout) {
-			fmt.Printf("--- stdout ---\n%s--- expected ---\n%s", resultStdout, expectedStdout)
-			t.Errorf("%s: stdout does not match expected", match)
+		result, err := os.ReadFile(fakeStdout.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(result) != tt.expected {
+			t.Errorf("%s: wrong output. expected=%q, got=%q", tt.input, tt.expected, result)
 		}
 	}
 }
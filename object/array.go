@@ -1,14 +1,7 @@
 package object
 
-import (
-	"bytes"
-	"hash/fnv"
-	"strings"
-)
-
 type Array struct {
 	Elements []Object
-	offset   int
 }
 
 func NewArray(slice []Object) *Array {
@@ -21,32 +14,59 @@ func NewArrayWithObjects(objs ...Object) *Array {
 	return NewArray(slice)
 }
 
-func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
-func (ao *Array) Inspect() string {
-	var out bytes.Buffer
-
-	length := len(ao.Elements)
-	elements := make([]string, length)
-	for index, element := range ao.Elements {
-		elements[index] = element.Inspect()
+func NewStringArray(values []string) *Array {
+	slice := make([]Object, len(values))
+	for i, value := range values {
+		slice[i] = NewString(value)
 	}
+	return NewArray(slice)
+}
 
-	out.WriteString("[")
-	out.WriteString(strings.Join(elements, ", "))
-	out.WriteString("]")
-
-	return out.String()
+func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
+func (ao *Array) Inspect() string {
+	return inspectObject(ao, map[Object]bool{})
 }
 
 func (ao *Array) HashKey() HashKey {
-	h := fnv.New64a()
-	h.Write([]byte(ao.Inspect()))
-
-	return HashKey{Type: ao.Type(), Value: h.Sum64()}
+	return HashKey{Type: ao.Type(), Value: HashBytes([]byte(ao.Inspect()))}
 }
 
 func init() {
 	objectMethods[ARRAY_OBJ] = map[string]ObjectMethod{
+		"iter": ObjectMethod{
+			description: "Returns a new iterator over the elements of the array.",
+			example: `🚀 > it = [1, 2].iter()
+🚀 > it.next()
+=> 1`,
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Array).Iter()
+			},
+		},
+		"each": ObjectMethod{
+			description: "Calls the function with every element and returns the array. Stops at the first error.",
+			example: `🚀 > [1, 2].each { |x| puts(x) }
+1
+2
+=> [1, 2]`,
+			argPattern: [][]string{
+				[]string{FUNCTION_OBJ, BUILTIN_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ARRAY_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				ao := o.(*Array)
+				for _, element := range ao.Elements {
+					if result := ApplyFunction(args[0], []Object{element}); IsError(result) {
+						return result
+					}
+				}
+				return ao
+			},
+		},
 		"size": ObjectMethod{
 			description: "Returns the amount of elements in the array.",
 			example: `🚀 > ["a", "b", 1, 2].size()
@@ -60,30 +80,26 @@ func init() {
 			},
 		},
 		"uniq": ObjectMethod{
-			description: "Returns a copy of the array with deduplicated elements. Raises an error if a element is not hashable.",
+			description: "Returns a copy of the array with deduplicated elements, keeping the first occurrence of each in order. Raises an error if a element is not hashable.",
 			example: `🚀 > ["a", 1, 1, 2].uniq()
-=> [1, 2, "a"]`,
+=> ["a", 1, 2]`,
 			returnPattern: [][]string{
 				[]string{ARRAY_OBJ, ERROR_OBJ},
 			},
 			method: func(o Object, _ []Object) Object {
 				ao := o.(*Array)
 
-				items := make(map[HashKey]Object)
+				seen := NewHash(nil)
+				newElements := []Object{}
 				for _, element := range ao.Elements {
 					helper, ok := element.(Hashable)
 					if !ok {
 						return NewErrorFormat("failed because element %s is not hashable", element.Type())
 					}
-					items[helper.HashKey()] = element
-				}
-
-				length := len(items)
-				newElements := make([]Object, length)
-				var idx int
-				for _, item := range items {
-					newElements[idx] = item
-					idx++
+					if _, ok := seen.Get(helper); !ok {
+						seen.Set(helper, TRUE)
+						newElements = append(newElements, element)
+					}
 				}
 
 				return NewArray(newElements)
@@ -201,16 +217,14 @@ func (ao *Array) InvokeMethod(method string, env Environment, args ...Object) Ob
 	return objectMethodLookup(ao, method, args)
 }
 
-func (ao *Array) Reset() {
-	ao.offset = 0
-}
-func (ao *Array) Next() (Object, Object, bool) {
-	if ao.offset < len(ao.Elements) {
-		ao.offset++
-
-		element := ao.Elements[ao.offset-1]
-		return element, NewInteger(int64(ao.offset - 1)), true
-	}
-
-	return nil, NewInteger(0), false
+// Iter returns an iterator over the elements and their indices.
+func (ao *Array) Iter() *Iterator {
+	var offset int
+	return NewIterator(func() (Object, Object, bool) {
+		if offset >= len(ao.Elements) {
+			return nil, nil, false
+		}
+		offset++
+		return ao.Elements[offset-1], NewInteger(int64(offset - 1)), true
+	})
 }
@@ -22,6 +22,8 @@ func TestArrayObject(t *testing.T) {
 		{"[1] == [1]", true},
 		{"[1] == [true]", false},
 		{"[1] == [true, 1]", false},
+		{"a = [1]; a.yoink(a); a", "[1, [...]]"},
+		{"a = [1]; a.yoink(a); b = [1]; b.yoink(b); a == b", true},
 	}
 
 	testInput(t, tests)
@@ -42,14 +44,19 @@ func TestArrayObjectMethods(t *testing.T) {
 		{`[1,2,3].index(true)`, -1},
 		{`[1,2,3].index()`, "to few arguments: want=1, got=0"},
 		{`a = []; b = []; foreach i in a { b.yoink(a[i]) }; a.size()==b.size()`, true},
+		{`a = [1, 2, 3]; s = 0; foreach x in a { foreach y in a { s = s + x * y } }; s`, 36},
 		{`[1,1,2].uniq().size()`, 2},
 		{`[true,true,2].uniq().size()`, 2},
 		{`["test","test",2].uniq().size()`, 2},
 		{`["12".reverse!()].uniq()`, "failed because element NULL is not hashable"},
+		{`["a", 1, 1, 2, "a"].uniq()`, `["a", 1, 2]`},
 		{"[].first()", "NULL"},
 		{"[1,2,3].first()", 1},
 		{"[].last()", "NULL"},
 		{"[1,2,3].last()", 3},
+		{"s = 0; [1,2,3].each { |x| s = s + x }; s", 6},
+		{"[1,2].each(|x| x)", "[1, 2]"},
+		{"[1].each { |x| x.nope() }", "undefined method `.nope()` for INTEGER"},
 	}
 
 	testInput(t, tests)
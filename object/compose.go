@@ -0,0 +1,102 @@
+package object
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/token"
+)
+
+// IsCallable reports whether obj can be called like a function.
+func IsCallable(obj Object) bool {
+	switch obj.(type) {
+	case *Function, *Builtin:
+		return true
+	}
+	return false
+}
+
+// NewComposedFunction returns a builtin calling second with the result of
+// first, like `first >> second`.
+func NewComposedFunction(first, second Object) *Builtin {
+	return NewBuiltin("compose", func(args ...Object) Object {
+		value := ApplyFunction(first, args)
+		if IsError(value) {
+			return value
+		}
+		return ApplyFunction(second, []Object{value})
+	})
+}
+
+// NewPartialFunction returns a builtin calling fn with bound followed by its
+// own arguments. A function can't be given more arguments than it has
+// parameters.
+func NewPartialFunction(fn Object, bound []Object) Object {
+	if !IsCallable(fn) {
+		return NewErrorFormat("cannot partially apply %s", fn.Type())
+	}
+	if function, ok := fn.(*Function); ok && len(bound) > len(function.Parameters) {
+		return NewErrorFormat("too many arguments to partially apply. got=%d, want at most %d", len(bound), len(function.Parameters))
+	}
+
+	bound = append([]Object{}, bound...)
+	return NewBuiltin("partial", func(args ...Object) Object {
+		return ApplyFunction(fn, append(append([]Object{}, bound...), args...))
+	})
+}
+
+// NewCurriedFunction returns a chain of arity builtins taking one argument
+// each. The last one calls fn with all collected arguments.
+func NewCurriedFunction(fn Object, arity int) Object {
+	if !IsCallable(fn) {
+		return NewErrorFormat("cannot curry %s", fn.Type())
+	}
+	if arity < 1 {
+		return NewErrorFormat("cannot curry a function without arguments")
+	}
+
+	return curried(fn, arity, nil)
+}
+
+// curried returns the builtin taking the next argument of a curried function
+// after the given ones were collected.
+func curried(fn Object, arity int, collected []Object) *Builtin {
+	return NewBuiltin("curry", func(args ...Object) Object {
+		if len(args) != 1 {
+			return NewErrorFormat("wrong number of arguments to curried function. got=%d, want=1", len(args))
+		}
+
+		// every call extends its own copy, a curried function can be reused
+		next := append(append(make([]Object, 0, arity), collected...), args[0])
+		if len(next) == arity {
+			return ApplyFunction(fn, next)
+		}
+		return curried(fn, arity, next)
+	})
+}
+
+// NewMethodReference returns a function calling the method name on its
+// argument, like `&:upcase`.
+func NewMethodReference(name string) *Function {
+	param := newIdentifier("x")
+	body := &ast.ObjectCall{
+		Token:  token.Token{Type: token.PERIOD, Literal: "."},
+		Object: param,
+		Call: &ast.Call{
+			Token:    token.Token{Type: token.LPAREN, Literal: "("},
+			Callable: newIdentifier(name),
+		},
+	}
+
+	return NewFunction([]*ast.Identifier{param}, NewEnvironment(), newBody(body))
+}
+
+func newIdentifier(name string) *ast.Identifier {
+	return &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+}
+
+func newBody(expression ast.Expression) *ast.Block {
+	tok := token.Token{Type: token.LBRACE, Literal: "{"}
+	return &ast.Block{
+		Token:      tok,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, Expression: expression}},
+	}
+}
@@ -0,0 +1,218 @@
+package object
+
+import "sort"
+
+// Counter tallies how often values were added. Values which were never
+// added have a count of 0.
+type Counter struct {
+	counts *Hash
+}
+
+func NewCounter() *Counter {
+	return &Counter{counts: NewHash(nil)}
+}
+
+func (c *Counter) Type() ObjectType { return COUNTER_OBJ }
+func (c *Counter) Inspect() string {
+	return inspectObject(c, map[Object]bool{})
+}
+
+// Count returns how often value was added.
+func (c *Counter) Count(value Hashable) int64 {
+	if count, ok := c.counts.Get(value); ok {
+		return count.(*Integer).Value
+	}
+	return 0
+}
+
+// Add increases the count of value by n and returns the new count.
+func (c *Counter) Add(value Hashable, n int64) int64 {
+	count := c.Count(value) + n
+	c.Set(value, count)
+	return count
+}
+
+// Set sets the count of value.
+func (c *Counter) Set(value Hashable, count int64) {
+	c.counts.Set(value, NewInteger(count))
+}
+
+// Update adds every value of the iterable once.
+func (c *Counter) Update(iterable Iterable) Object {
+	values, err := Collect(iterable)
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		hashable, ok := value.(Hashable)
+		if !ok {
+			return NewErrorFormat("unusable as counter key: %s", value.Type())
+		}
+		c.Add(hashable, 1)
+	}
+	return nil
+}
+
+// Len returns the amount of distinct values.
+func (c *Counter) Len() int {
+	return c.counts.Len()
+}
+
+// Total returns the sum of all counts.
+func (c *Counter) Total() int64 {
+	var total int64
+	for _, pair := range c.counts.Pairs() {
+		total += pair.Value.(*Integer).Value
+	}
+	return total
+}
+
+// MostCommon returns the values and their counts, the highest count first.
+// Values with the same count are ordered like the keys of a hash.
+func (c *Counter) MostCommon() []HashPair {
+	pairs := sortedPairs(c.counts)
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Value.(*Integer).Value > pairs[j].Value.(*Integer).Value
+	})
+	return pairs
+}
+
+func init() {
+	objectMethods[COUNTER_OBJ] = map[string]ObjectMethod{
+		"add": ObjectMethod{
+			description: "Increases the count of the value by one and returns the new count.",
+			example: `🚀 > c = collections.counter(["a"])
+🚀 > c.add("a")
+=> 2`,
+			argPattern: [][]string{
+				hashableTypes,
+			},
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return NewInteger(o.(*Counter).Add(args[0].(Hashable), 1))
+			},
+		},
+		"update": ObjectMethod{
+			description: "Adds every value of the array, string or other iterable and returns the counter.",
+			example: `🚀 > collections.counter().update("abba")
+=> counter({"a": 2, "b": 2})`,
+			argPattern: [][]string{
+				[]string{ARRAY_OBJ, STRING_OBJ, HASH_OBJ, SET_OBJ, ITERATOR_OBJ, DEQUE_OBJ, HEAP_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{COUNTER_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				if err := o.(*Counter).Update(args[0].(Iterable)); err != nil {
+					return err
+				}
+				return o
+			},
+		},
+		"count": ObjectMethod{
+			description: "Returns how often the value was added, `c.count(x)` is the same as `c[x]`.",
+			example: `🚀 > collections.counter("abba").count("c")
+=> 0`,
+			argPattern: [][]string{
+				hashableTypes,
+			},
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return NewInteger(o.(*Counter).Count(args[0].(Hashable)))
+			},
+		},
+		"most_common": ObjectMethod{
+			description: "Returns pairs of the values and their counts, the highest count first. If a number is given only that many pairs are returned.",
+			example: `🚀 > collections.counter("abbccc").most_common(2)
+=> [["c", 3], ["b", 2]]`,
+			argsOptional: true,
+			argPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ARRAY_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				pairs := o.(*Counter).MostCommon()
+				if len(args) > 0 {
+					if n := args[0].(*Integer).Value; n >= 0 && n < int64(len(pairs)) {
+						pairs = pairs[:n]
+					}
+				}
+
+				result := make([]Object, len(pairs))
+				for i, pair := range pairs {
+					result[i] = NewArray([]Object{pair.Key, pair.Value})
+				}
+				return NewArray(result)
+			},
+		},
+		"total": ObjectMethod{
+			description: "Returns the sum of all counts.",
+			example: `🚀 > collections.counter("abba").total()
+=> 4`,
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewInteger(o.(*Counter).Total())
+			},
+		},
+		"size": ObjectMethod{
+			description: "Returns the amount of distinct values.",
+			example: `🚀 > collections.counter("abba").size()
+=> 2`,
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewInteger(int64(o.(*Counter).Len()))
+			},
+		},
+		"to_h": ObjectMethod{
+			description: "Returns a hash of the values and their counts.",
+			example: `🚀 > collections.counter("abb").to_h()
+=> {"a": 1, "b": 2}`,
+			returnPattern: [][]string{
+				[]string{HASH_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewHash(o.(*Counter).counts.Pairs())
+			},
+		},
+		"iter": ObjectMethod{
+			description: "Returns a new iterator over the values and their counts, the highest count first.",
+			example: `🚀 > collections.counter("abb").iter().next()
+=> "b"`,
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Counter).Iter()
+			},
+		},
+	}
+}
+
+func (c *Counter) InvokeMethod(method string, env Environment, args ...Object) Object {
+	return objectMethodLookup(c, method, args)
+}
+
+// Iter returns an iterator over the values and their counts, the highest
+// count first. Like for hashes the values are the keys of the iterator.
+func (c *Counter) Iter() *Iterator {
+	pairs := c.MostCommon()
+
+	var offset int
+	return NewIterator(func() (Object, Object, bool) {
+		if offset >= len(pairs) {
+			return nil, nil, false
+		}
+		offset++
+		return pairs[offset-1].Key, pairs[offset-1].Value, true
+	})
+}
@@ -0,0 +1,27 @@
+package object_test
+
+import (
+	"testing"
+)
+
+func TestCounterObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`collections.counter("abbccc").inspect()`, `counter({"c": 3, "b": 2, "a": 1})`},
+		{`collections.counter([:b, :a, :b]).inspect()`, `counter({b: 2, a: 1})`},
+		{`collections.counter().inspect()`, `counter({})`},
+		{`c = collections.counter(); [c.add("a"), c.add("a"), c.count("a"), c.count("b")]`, "[1, 2, 2, 0]"},
+		{`collections.counter("ab").update(["a", "c"]).most_common()`, `[["a", 2], ["b", 1], ["c", 1]]`},
+		{`collections.counter("abbccc").most_common(1)`, `[["c", 3]]`},
+		{`collections.counter("ab").most_common(5)`, `[["a", 1], ["b", 1]]`},
+		{`collections.counter("abba").total()`, 4},
+		{`collections.counter("abba").size()`, 2},
+		{`collections.counter("abb").to_h().inspect()`, `{"a": 1, "b": 2}`},
+		{`collections.counter("abb").iter().to_a()`, `["b", "a"]`},
+		{`c = collections.counter("ab"); c["a"] += 2; c["z"] = 5; [c["a"], c["z"], c["y"]]`, "[3, 5, 0]"},
+		{`c = collections.counter(); c["a"] = "b"`, "expected INTEGER object, got STRING"},
+		{`collections.counter([-> { 1 }])`, "unusable as counter key: FUNCTION"},
+		{`collections.counter(1.5)`, "argument 1 to `collections.counter` must be iterable, got=FLOAT"},
+	}
+
+	testInput(t, tests)
+}
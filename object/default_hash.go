@@ -0,0 +1,73 @@
+package object
+
+// DefaultHash is a hash which creates the value of a missing key when it is
+// read. The default is either a function called without arguments or a
+// value which is copied for every key, so keys never share an array or
+// hash. It supports all methods of hashes.
+type DefaultHash struct {
+	Hash    *Hash
+	Default Object
+}
+
+func NewDefaultHash(defaultValue Object) *DefaultHash {
+	return &DefaultHash{Hash: NewHash(nil), Default: defaultValue}
+}
+
+func (d *DefaultHash) Type() ObjectType { return DEFAULT_HASH_OBJ }
+func (d *DefaultHash) Inspect() string {
+	return inspectObject(d, map[Object]bool{})
+}
+
+// Get returns the value of key. A missing key is set to a new default value
+// first.
+func (d *DefaultHash) Get(key Hashable) Object {
+	if value, ok := d.Hash.Get(key); ok {
+		return value
+	}
+
+	var value Object
+	if IsCallable(d.Default) {
+		value = ApplyFunction(d.Default, []Object{})
+		if IsError(value) {
+			return value
+		}
+	} else {
+		value = copyKey(d.Default, map[Object]Object{})
+	}
+
+	d.Hash.Set(key, value)
+	return value
+}
+
+func init() {
+	objectMethods[DEFAULT_HASH_OBJ] = map[string]ObjectMethod{
+		"to_h": ObjectMethod{
+			description: "Returns the hash holding the pairs. Changes to it also change the default hash.",
+			example: `🚀 > d = collections.default_hash(0)
+🚀 > d["a"] += 1
+🚀 > d.to_h()
+=> {"a": 1}`,
+			returnPattern: [][]string{
+				[]string{HASH_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*DefaultHash).Hash
+			},
+		},
+	}
+}
+
+// InvokeMethod calls the methods of hashes on the underlying hash.
+func (d *DefaultHash) InvokeMethod(method string, env Environment, args ...Object) Object {
+	if _, ok := objectMethods[DEFAULT_HASH_OBJ][method]; !ok {
+		if objMethod, ok := objectMethods[HASH_OBJ][method]; ok {
+			return objMethod.Call(d.Hash, args)
+		}
+	}
+	return objectMethodLookup(d, method, args)
+}
+
+// Iter returns an iterator over the keys and values like the one of hashes.
+func (d *DefaultHash) Iter() *Iterator {
+	return d.Hash.Iter()
+}
@@ -0,0 +1,21 @@
+package object_test
+
+import (
+	"testing"
+)
+
+func TestDefaultHashObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`d = collections.default_hash(0); d["a"] += 1; d["a"] += 1; d.inspect()`, `default_hash({"a": 2})`},
+		{`d = collections.default_hash(-> { [] }); d[1].yoink("x"); d[2]; d.inspect()`, `default_hash({1: ["x"], 2: []})`},
+		{`d = collections.default_hash([]); d[1].yoink("x"); d[2]`, "[]"},
+		{`d = collections.default_hash(0); d["a"]; d.keys()`, `["a"]`},
+		{`d = collections.default_hash(0); d["a"] = 5; d.values()`, "[5]"},
+		{`d = collections.default_hash(0); d[(:a)] = 1; d.to_h().inspect()`, `{a: 1}`},
+		{`d = collections.default_hash(0); d.to_h()["a"] = 1; d["a"]`, 1},
+		{`d = collections.default_hash(-> { 5 % 0 }); d["a"]`, "division by zero not allowed"},
+		{`collections.default_hash()`, "wrong number of arguments to `collections.default_hash`. got=0, want=1"},
+	}
+
+	testInput(t, tests)
+}
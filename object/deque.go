@@ -0,0 +1,239 @@
+package object
+
+// Deque is a double-ended queue. Values are pushed and popped at both ends
+// in constant time, they are kept in a ring buffer which grows as needed.
+type Deque struct {
+	buffer []Object
+	head   int // position of the first value in buffer
+	length int
+}
+
+// NewDeque returns a deque of the given values, the first one in front.
+func NewDeque(values []Object) *Deque {
+	d := &Deque{}
+	for _, value := range values {
+		d.PushBack(value)
+	}
+	return d
+}
+
+func (d *Deque) Type() ObjectType { return DEQUE_OBJ }
+func (d *Deque) Inspect() string {
+	return inspectObject(d, map[Object]bool{})
+}
+
+func (d *Deque) Len() int {
+	return d.length
+}
+
+// at returns the position in buffer of the value with index i.
+func (d *Deque) at(i int) int {
+	return (d.head + i) % len(d.buffer)
+}
+
+// grow doubles the buffer once it is full.
+func (d *Deque) grow() {
+	if d.length < len(d.buffer) {
+		return
+	}
+
+	size := 2 * len(d.buffer)
+	if size == 0 {
+		size = 8
+	}
+	d.buffer = append(d.Values(), make([]Object, size-d.length)...)
+	d.head = 0
+}
+
+func (d *Deque) PushBack(value Object) {
+	d.grow()
+	d.buffer[d.at(d.length)] = value
+	d.length++
+}
+
+func (d *Deque) PushFront(value Object) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buffer)) % len(d.buffer)
+	d.buffer[d.head] = value
+	d.length++
+}
+
+// PopBack removes and returns the last value, or returns NULL if the deque
+// is empty.
+func (d *Deque) PopBack() Object {
+	if d.length == 0 {
+		return NULL
+	}
+	pos := d.at(d.length - 1)
+	value := d.buffer[pos]
+	d.buffer[pos] = nil
+	d.length--
+	return value
+}
+
+// PopFront removes and returns the first value, or returns NULL if the
+// deque is empty.
+func (d *Deque) PopFront() Object {
+	if d.length == 0 {
+		return NULL
+	}
+	value := d.buffer[d.head]
+	d.buffer[d.head] = nil
+	d.head = d.at(1)
+	d.length--
+	return value
+}
+
+// Get returns the value with index i, negative indices count from the end.
+// It returns NULL if there is no such value.
+func (d *Deque) Get(i int) Object {
+	if i < 0 {
+		i += d.length
+	}
+	if i < 0 || i >= d.length {
+		return NULL
+	}
+	return d.buffer[d.at(i)]
+}
+
+// Values returns the values from front to back.
+func (d *Deque) Values() []Object {
+	values := make([]Object, d.length)
+	for i := range values {
+		values[i] = d.buffer[d.at(i)]
+	}
+	return values
+}
+
+func init() {
+	objectMethods[DEQUE_OBJ] = map[string]ObjectMethod{
+		"push": ObjectMethod{
+			description: "Adds the value to the back and returns the deque.",
+			example: `🚀 > collections.deque([1]).push(2)
+=> deque([1, 2])`,
+			argPattern: [][]string{
+				valueTypes,
+			},
+			returnPattern: [][]string{
+				[]string{DEQUE_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				o.(*Deque).PushBack(args[0])
+				return o
+			},
+		},
+		"push_front": ObjectMethod{
+			description: "Adds the value to the front and returns the deque.",
+			example: `🚀 > collections.deque([1]).push_front(2)
+=> deque([2, 1])`,
+			argPattern: [][]string{
+				valueTypes,
+			},
+			returnPattern: [][]string{
+				[]string{DEQUE_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				o.(*Deque).PushFront(args[0])
+				return o
+			},
+		},
+		"pop": ObjectMethod{
+			description: "Removes and returns the last value, or returns `null` if the deque is empty.",
+			example: `🚀 > collections.deque([1, 2]).pop()
+=> 2`,
+			returnPattern: [][]string{
+				valueTypes,
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Deque).PopBack()
+			},
+		},
+		"pop_front": ObjectMethod{
+			description: "Removes and returns the first value, or returns `null` if the deque is empty.",
+			example: `🚀 > collections.deque([1, 2]).pop_front()
+=> 1`,
+			returnPattern: [][]string{
+				valueTypes,
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Deque).PopFront()
+			},
+		},
+		"first": ObjectMethod{
+			description: "Returns the first value, or `null` if the deque is empty.",
+			example: `🚀 > collections.deque([1, 2]).first()
+=> 1`,
+			returnPattern: [][]string{
+				valueTypes,
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Deque).Get(0)
+			},
+		},
+		"last": ObjectMethod{
+			description: "Returns the last value, or `null` if the deque is empty.",
+			example: `🚀 > collections.deque([1, 2]).last()
+=> 2`,
+			returnPattern: [][]string{
+				valueTypes,
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Deque).Get(-1)
+			},
+		},
+		"size": ObjectMethod{
+			description: "Returns the amount of values in the deque.",
+			example: `🚀 > collections.deque([1, 2]).size()
+=> 2`,
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewInteger(int64(o.(*Deque).Len()))
+			},
+		},
+		"empty?": ObjectMethod{
+			description: "Returns `true` if the deque has no values.",
+			example: `🚀 > collections.deque().empty?()
+=> true`,
+			returnPattern: [][]string{
+				[]string{BOOLEAN_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return nativeBool(o.(*Deque).Len() == 0)
+			},
+		},
+		"to_a": ObjectMethod{
+			description: "Returns the values from front to back.",
+			example: `🚀 > collections.deque([1]).push_front(0).to_a()
+=> [0, 1]`,
+			returnPattern: [][]string{
+				[]string{ARRAY_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewArray(o.(*Deque).Values())
+			},
+		},
+		"iter": ObjectMethod{
+			description: "Returns a new iterator over the values from front to back.",
+			example: `🚀 > collections.deque([1, 2]).iter().next()
+=> 1`,
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Deque).Iter()
+			},
+		},
+	}
+}
+
+func (d *Deque) InvokeMethod(method string, env Environment, args ...Object) Object {
+	return objectMethodLookup(d, method, args)
+}
+
+// Iter returns an iterator over a snapshot of the values from front to back
+// and their indices.
+func (d *Deque) Iter() *Iterator {
+	return iterateObjects(d.Values())
+}
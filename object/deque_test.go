@@ -0,0 +1,58 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+func TestDequeObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`collections.deque([1, 2]).inspect()`, "deque([1, 2])"},
+		{`collections.deque().inspect()`, "deque([])"},
+		{`collections.deque("ab").push_front(1).push(2).to_a()`, `[1, "a", "b", 2]`},
+		{`d = collections.deque([1, 2, 3]); [d.pop(), d.pop_front(), d.to_a()]`, "[3, 1, [2]]"},
+		{`d = collections.deque(); [d.pop(), d.pop_front()]`, "[null, null]"},
+		{`collections.deque([1, 2]).first()`, 1},
+		{`collections.deque([1, 2]).last()`, 2},
+		{`collections.deque().first()`, "null"},
+		{`collections.deque([1, 2, 3]).size()`, 3},
+		{`collections.deque().empty?()`, true},
+		{`collections.deque([1, 2]).iter().to_a()`, "[1, 2]"},
+		{`d = collections.deque([1, 2, 3]); [d[0], d[-1], d[3]]`, "[1, 3, null]"},
+		{`collections.deque(1.5)`, "argument 1 to `collections.deque` must be iterable, got=FLOAT"},
+		{`collections.deque([1], [2])`, "wrong number of arguments to `collections.deque`. got=2, want=0..1"},
+	}
+
+	testInput(t, tests)
+}
+
+func TestDequeRingBuffer(t *testing.T) {
+	d := object.NewDeque(nil)
+	var expected []int64
+
+	// alternate both ends so the ring buffer wraps around and grows
+	for i := int64(0); i < 50; i++ {
+		if i%3 == 0 {
+			d.PushFront(object.NewInteger(i))
+			expected = append([]int64{i}, expected...)
+		} else {
+			d.PushBack(object.NewInteger(i))
+			expected = append(expected, i)
+		}
+		if i%5 == 0 {
+			d.PopFront()
+			expected = expected[1:]
+		}
+	}
+
+	values := d.Values()
+	if len(values) != len(expected) || d.Len() != len(expected) {
+		t.Fatalf("wrong length. expected=%d, got=%d", len(expected), len(values))
+	}
+	for i, value := range values {
+		if value.(*object.Integer).Value != expected[i] {
+			t.Errorf("wrong value at %d. expected=%d, got=%s", i, expected[i], value.Inspect())
+		}
+	}
+}
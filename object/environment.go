@@ -3,6 +3,8 @@ package object
 import (
 	"strings"
 	"unicode"
+
+	"github.com/flipez/rocket-lang/ast"
 )
 
 func NewEnvironment() *Environment {
@@ -13,9 +15,42 @@ func NewEnvironment() *Environment {
 type Environment struct {
 	store map[string]Object
 	outer *Environment
+
+	// names are the variables the resolver found in the scope of the
+	// environment, slots hold their values or nil while they are unset
+	names *ast.Locals
+	slots []Object
+
+	yield func(Object) Object
+
+	// captured is set once a function was created in the environment or in
+	// one enclosed by it, the function keeps using it after it was left
+	captured bool
+}
+
+// NewScopeEnvironment returns an environment with a slot for each of names,
+// the variables the resolver found in a scope. Other names are stored by
+// name like in any environment.
+func NewScopeEnvironment(outer *Environment, names *ast.Locals) *Environment {
+	return &Environment{outer: outer, names: names, slots: make([]Object, names.Len())}
+}
+
+// Declare adds slots for names to the environment of a program. names
+// start with the names the environment has already, as the variables of a
+// REPL grow with every line.
+func (e *Environment) Declare(names *ast.Locals) {
+	if names.Len() <= e.names.Len() {
+		return
+	}
+	e.names = names
+	e.slots = append(e.slots, make([]Object, names.Len()-len(e.slots))...)
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
+	if slot := e.slot(name); slot != -1 && e.slots[slot] != nil {
+		return e.slots[slot], true
+	}
+
 	obj, ok := e.store[name]
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.Get(name)
@@ -23,7 +58,52 @@ func (e *Environment) Get(name string) (Object, bool) {
 	return obj, ok
 }
 
+// Scope returns the environment depth levels up from this one, or nil if
+// there are less.
+func (e *Environment) Scope(depth int) *Environment {
+	env := e
+	for ; depth > 0 && env != nil; depth-- {
+		env = env.outer
+	}
+	return env
+}
+
+// GetAt returns the value in slot of the environment depth levels up, or
+// nil if it is unset.
+func (e *Environment) GetAt(depth, slot int) Object {
+	env := e.Scope(depth)
+	if env == nil || slot >= len(env.slots) {
+		return nil
+	}
+	return env.slots[slot]
+}
+
+// SetAt assigns val to slot of the environment depth levels up. It reports
+// whether the slot exists.
+func (e *Environment) SetAt(depth, slot int, val Object) bool {
+	env := e.Scope(depth)
+	if env == nil || slot >= len(env.slots) {
+		return false
+	}
+	env.slots[slot] = val
+	return true
+}
+
+func (e *Environment) slot(name string) int {
+	return e.names.Slot(name)
+}
+
+// Set assigns val to name. A name which is not defined in this environment
+// but in an outer one is assigned there.
 func (e *Environment) Set(name string, val Object) Object {
+	if slot := e.slot(name); slot != -1 {
+		e.slots[slot] = val
+		return val
+	}
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return val
+	}
 	if e.outer != nil {
 		_, ok := e.outer.Get(name)
 		if ok {
@@ -31,10 +111,64 @@ func (e *Environment) Set(name string, val Object) Object {
 			return val
 		}
 	}
+	return e.Define(name, val)
+}
+
+// Define defines name in this environment, hiding a name of an outer one.
+func (e *Environment) Define(name string, val Object) Object {
+	if slot := e.slot(name); slot != -1 {
+		e.slots[slot] = val
+		return val
+	}
+	if e.store == nil {
+		e.store = make(map[string]Object)
+	}
 	e.store[name] = val
 	return val
 }
 
+// capture marks the environment and the ones enclosing it as captured.
+func (e *Environment) capture() {
+	for env := e; env != nil && !env.captured; env = env.outer {
+		env.captured = true
+	}
+}
+
+// Captured reports whether a function was created in the environment or in
+// one enclosed by it.
+func (e *Environment) Captured() bool {
+	return e.captured
+}
+
+// Copy returns an environment with the same outer environment and a copy of
+// the variables of this one. The copy is not captured.
+func (e *Environment) Copy() *Environment {
+	env := &Environment{outer: e.outer, names: e.names, yield: e.yield}
+	env.slots = append([]Object{}, e.slots...)
+	if e.store != nil {
+		env.store = make(map[string]Object, len(e.store))
+		for name, value := range e.store {
+			env.store[name] = value
+		}
+	}
+	return env
+}
+
+// SetYield marks the environment as the one of a running generator. fn is
+// called for every yielded value.
+func (e *Environment) SetYield(fn func(Object) Object) {
+	e.yield = fn
+}
+
+// Yield returns the yield function of the innermost generator or nil if the
+// environment does not belong to a generator.
+func (e *Environment) Yield() func(Object) Object {
+	if e.yield == nil && e.outer != nil {
+		return e.outer.Yield()
+	}
+	return e.yield
+}
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
@@ -44,7 +178,7 @@ func NewEnclosedEnvironment(outer *Environment) *Environment {
 func (e *Environment) Names(prefix string) []string {
 	var ret []string
 
-	for key := range e.store {
+	for key := range e.variables() {
 		if strings.HasPrefix(key, prefix) {
 			ret = append(ret, key)
 		}
@@ -59,15 +193,32 @@ func (e *Environment) Names(prefix string) []string {
 }
 
 func (e *Environment) Exported() *Hash {
-	pairs := make(map[HashKey]HashPair)
+	hash := NewHash(nil)
 
-	for k, v := range e.store {
+	for k, v := range e.variables() {
 		// Replace this with checking for "Import" token
 		if unicode.IsUpper(rune(k[0])) {
-			s := NewString(k)
-			pairs[s.HashKey()] = HashPair{Key: s, Value: v}
+			hash.Set(NewString(k), v)
 		}
 	}
 
-	return NewHash(pairs)
+	return hash
+}
+
+// variables returns the set variables of the environment by name.
+func (e *Environment) variables() map[string]Object {
+	if e.names.Len() == 0 {
+		return e.store
+	}
+
+	vars := make(map[string]Object, len(e.store)+e.names.Len())
+	for k, v := range e.store {
+		vars[k] = v
+	}
+	for i, name := range e.names.Names {
+		if e.slots[i] != nil {
+			vars[name] = e.slots[i]
+		}
+	}
+	return vars
 }
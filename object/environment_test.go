@@ -3,6 +3,7 @@ package object_test
 import (
 	"testing"
 
+	"github.com/flipez/rocket-lang/ast"
 	"github.com/flipez/rocket-lang/object"
 )
 
@@ -140,3 +141,42 @@ func TestEnvironment(t *testing.T) {
 		t.Errorf(`v, ok := child.Get("b"): expected 'ok' to be true, but got %t`, ok)
 	}
 }
+
+func TestScopeEnvironment(t *testing.T) {
+	parent := object.NewEnvironment()
+	parent.Declare(ast.NewLocals([]string{"a"}))
+	child := object.NewScopeEnvironment(parent, ast.NewLocals([]string{"b"}))
+
+	if v := child.GetAt(1, 0); v != nil {
+		t.Errorf("expected unset slot to be nil, got %v", v)
+	}
+
+	parent.Set("a", object.NewInteger(1))
+	if v := child.GetAt(1, 0); v == nil || v.Inspect() != "1" {
+		t.Errorf("expected a to be 1, got %v", v)
+	}
+
+	if !child.SetAt(0, 0, object.NewInteger(2)) {
+		t.Errorf("expected slot of b to exist")
+	}
+	if v, ok := child.Get("b"); !ok || v.Inspect() != "2" {
+		t.Errorf("expected b to be 2, got %v", v)
+	}
+	if child.SetAt(0, 1, object.NULL) || child.SetAt(2, 0, object.NULL) {
+		t.Errorf("expected missing slots to be reported")
+	}
+
+	// names without a slot are stored by name
+	child.Set("c", object.NewInteger(3))
+	if v, ok := child.Get("c"); !ok || v.Inspect() != "3" {
+		t.Errorf("expected c to be 3, got %v", v)
+	}
+
+	parent.Declare(ast.NewLocals([]string{"a", "d"}))
+	if v := parent.GetAt(0, 0); v == nil || v.Inspect() != "1" {
+		t.Errorf("expected a to keep its value, got %v", v)
+	}
+	if !parent.SetAt(0, 1, object.TRUE) {
+		t.Errorf("expected slot of d to exist")
+	}
+}
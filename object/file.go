@@ -213,6 +213,16 @@ func readFile(o Object, _ []Object) Object {
 	if f.Handle == nil {
 		return NewError("Invalid file handle.")
 	}
+	// pipes can't be rewound and are read from their current position
+	if !f.seekable() {
+		file, err := ioutil.ReadAll(f.Handle)
+		if err != nil {
+			return NewError(err)
+		}
+		f.Position += int64(len(file))
+		return NewString(string(file))
+	}
+
 	if _, err := f.Handle.Seek(0, 0); err != nil {
 		return NewError(err)
 	}
@@ -228,3 +238,8 @@ func readFile(o Object, _ []Object) Object {
 	f.Position = 0
 	return NewString(string(file))
 }
+
+func (f *File) seekable() bool {
+	info, err := f.Handle.Stat()
+	return err == nil && info.Mode().IsRegular()
+}
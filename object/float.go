@@ -2,7 +2,6 @@ package object
 
 import (
 	"fmt"
-	"hash/fnv"
 	"strconv"
 )
 
@@ -17,10 +16,7 @@ func NewFloat(f float64) *Float {
 func (f *Float) Inspect() string  { return f.toString() }
 func (f *Float) Type() ObjectType { return FLOAT_OBJ }
 func (f *Float) HashKey() HashKey {
-	h := fnv.New64a()
-	h.Write([]byte(fmt.Sprintf("%f", f.Value)))
-
-	return HashKey{Type: f.Type(), Value: h.Sum64()}
+	return HashKey{Type: f.Type(), Value: HashBytes([]byte(fmt.Sprintf("%f", f.Value)))}
 }
 
 func init() {
@@ -11,9 +11,15 @@ type Function struct {
 	Parameters []*ast.Identifier
 	Body       *ast.Block
 	Env        *Environment
+	Generator  bool
+
+	// Locals are the variables the resolver found in the body, they get a
+	// slot in the environment of every call
+	Locals *ast.Locals
 }
 
 func NewFunction(params []*ast.Identifier, env *Environment, body *ast.Block) *Function {
+	env.capture()
 	return &Function{
 		Parameters: params,
 		Env:        env,
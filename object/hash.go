@@ -1,26 +1,32 @@
 package object
 
 import (
-	"bytes"
-	"fmt"
 	"hash/fnv"
-	"strings"
 )
 
+// Hash maps keys to values. Keys are distributed into buckets by their
+// HashKey and compared with CompareObjects inside a bucket, so keys whose
+// hash values collide are kept apart.
+//
+// Strings, arrays and hashes are mutable, changing them after they were used
+// as key would leave the pair in the wrong bucket. Set therefore stores a
+// copy of such keys.
 type Hash struct {
-	Pairs  map[HashKey]HashPair
-	offset int
+	buckets map[HashKey][]HashPair
+	length  int
 }
 
-func NewHash(pairs map[HashKey]HashPair) *Hash {
-	if pairs == nil {
-		pairs = make(map[HashKey]HashPair)
+// NewHash returns a hash containing the given pairs.
+func NewHash(pairs []HashPair) *Hash {
+	h := &Hash{buckets: make(map[HashKey][]HashPair)}
+	for _, pair := range pairs {
+		h.Set(pair.Key, pair.Value)
 	}
-	return &Hash{Pairs: pairs}
+	return h
 }
 
 type HashPair struct {
-	Key   Object
+	Key   Hashable
 	Value Object
 }
 
@@ -29,34 +35,169 @@ type HashKey struct {
 	Value uint64
 }
 
+// HashBytes computes the hash values of strings, floats, arrays and hashes.
+// Tests replace it to provoke collisions.
+var HashBytes = func(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
 func (h *Hash) Type() ObjectType { return HASH_OBJ }
 func (h *Hash) Inspect() string {
-	var out bytes.Buffer
-
-	length := len(h.Pairs)
-	pairs := make([]string, length)
-	var index int
-	for _, pair := range h.Pairs {
-		pairs[index] = fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect())
-		index++
+	return inspectObject(h, map[Object]bool{})
+}
+
+func (h *Hash) HashKey() HashKey {
+	return HashKey{Type: h.Type(), Value: HashBytes([]byte(h.Inspect()))}
+}
+
+// Get returns the value stored for key.
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	for _, pair := range h.buckets[key.HashKey()] {
+		if CompareObjects(pair.Key, key) {
+			return pair.Value, true
+		}
 	}
+	return nil, false
+}
 
-	out.WriteString("{")
-	out.WriteString(strings.Join(pairs, ", "))
-	out.WriteString("}")
+// Set stores value for key, replacing the value of an equal key.
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	bucket := h.buckets[hashKey]
+	for i, pair := range bucket {
+		if CompareObjects(pair.Key, key) {
+			bucket[i].Value = value
+			return
+		}
+	}
 
-	return out.String()
+	h.buckets[hashKey] = append(bucket, HashPair{Key: copyKey(key, map[Object]Object{}).(Hashable), Value: value})
+	h.length++
 }
 
-func (h *Hash) HashKey() HashKey {
-	ha := fnv.New64a()
-	ha.Write([]byte(h.Inspect()))
+// Delete removes the pair of key and reports whether there was one.
+func (h *Hash) Delete(key Hashable) bool {
+	hashKey := key.HashKey()
+	bucket := h.buckets[hashKey]
+	for i, pair := range bucket {
+		if CompareObjects(pair.Key, key) {
+			if len(bucket) == 1 {
+				delete(h.buckets, hashKey)
+			} else {
+				h.buckets[hashKey] = append(bucket[:i:i], bucket[i+1:]...)
+			}
+			h.length--
+			return true
+		}
+	}
+	return false
+}
+
+// Member returns the value stored for the key of name.
+func (h *Hash) Member(name string) (Object, bool) {
+	return h.Get(h.MemberKey(name))
+}
+
+// MemberKey returns the key reached by `h.name`: the string key name, or
+// the symbol key of that name if the hash has only that one.
+func (h *Hash) MemberKey(name string) Hashable {
+	key := NewString(name)
+	if _, ok := h.Get(key); ok {
+		return key
+	}
+	if symbol, ok := LookupSymbol(name); ok {
+		if _, ok := h.Get(symbol); ok {
+			return symbol
+		}
+	}
+	return key
+}
+
+// Len returns the amount of pairs.
+func (h *Hash) Len() int {
+	return h.length
+}
+
+// Pairs returns all pairs in no particular order.
+func (h *Hash) Pairs() []HashPair {
+	pairs := make([]HashPair, 0, h.length)
+	for _, bucket := range h.buckets {
+		pairs = append(pairs, bucket...)
+	}
+	return pairs
+}
+
+// copyKey returns a deep copy of mutable keys. copies maps already copied
+// containers to their copy to preserve cycles.
+func copyKey(key Object, copies map[Object]Object) Object {
+	if c, ok := copies[key]; ok {
+		return c
+	}
 
-	return HashKey{Type: h.Type(), Value: ha.Sum64()}
+	switch key := key.(type) {
+	case *String:
+		return NewString(key.Value)
+	case *Array:
+		c := NewArray(make([]Object, len(key.Elements)))
+		copies[key] = c
+		for i, element := range key.Elements {
+			c.Elements[i] = copyKey(element, copies)
+		}
+		return c
+	case *Set:
+		return NewSet(key.Elements())
+	case *Hash:
+		c := &Hash{buckets: make(map[HashKey][]HashPair, len(key.buckets)), length: key.length}
+		copies[key] = c
+		for hashKey, bucket := range key.buckets {
+			pairs := make([]HashPair, len(bucket))
+			for i, pair := range bucket {
+				pairs[i] = HashPair{Key: copyKey(pair.Key, copies).(Hashable), Value: copyKey(pair.Value, copies)}
+			}
+			c.buckets[hashKey] = pairs
+		}
+		return c
+	default:
+		return key
+	}
 }
 
 func init() {
 	objectMethods[HASH_OBJ] = map[string]ObjectMethod{
+		"iter": ObjectMethod{
+			description: "Returns a new iterator over the keys of the hash.",
+			example: `🚀 > {"a": 1}.iter().next()
+=> "a"`,
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Hash).Iter()
+			},
+		},
+		"each": ObjectMethod{
+			description: "Calls the function with every key and value and returns the hash. Stops at the first error.",
+			example: `🚀 > {"a": 1}.each { |k, v| puts(k, v) }
+a
+1
+=> {"a": 1}`,
+			argPattern: [][]string{
+				[]string{FUNCTION_OBJ, BUILTIN_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{HASH_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				for _, pair := range o.(*Hash).Pairs() {
+					if result := ApplyFunction(args[0], []Object{pair.Key, pair.Value}); IsError(result) {
+						return result
+					}
+				}
+				return o
+			},
+		},
 		"keys": ObjectMethod{
 			description: "Returns the keys of the hash.",
 			example: `🚀 > {"a": "1", "b": "2"}.keys()
@@ -65,14 +206,11 @@ func init() {
 				[]string{ARRAY_OBJ},
 			},
 			method: func(o Object, _ []Object) Object {
-				h := o.(*Hash)
-
-				keys := make([]Object, len(h.Pairs))
+				pairs := o.(*Hash).Pairs()
 
-				i := 0
-				for _, k := range h.Pairs {
-					keys[i] = k.Key
-					i++
+				keys := make([]Object, len(pairs))
+				for i, pair := range pairs {
+					keys[i] = pair.Key
 				}
 
 				return NewArray(keys)
@@ -86,14 +224,11 @@ func init() {
 				[]string{ARRAY_OBJ},
 			},
 			method: func(o Object, _ []Object) Object {
-				h := o.(*Hash)
+				pairs := o.(*Hash).Pairs()
 
-				values := make([]Object, len(h.Pairs))
-
-				i := 0
-				for _, k := range h.Pairs {
-					values[i] = k.Value
-					i++
+				values := make([]Object, len(pairs))
+				for i, pair := range pairs {
+					values[i] = pair.Value
 				}
 
 				return NewArray(values)
@@ -107,22 +242,17 @@ func (h *Hash) InvokeMethod(method string, env Environment, args ...Object) Obje
 
 }
 
-func (h *Hash) Reset() {
-	h.offset = 0
-}
-
-func (h *Hash) Next() (Object, Object, bool) {
-	if h.offset < len(h.Pairs) {
-		idx := 0
+// Iter returns an iterator over the keys and values. It works on a snapshot
+// of the pairs, changes to the hash during the iteration are not visible.
+func (h *Hash) Iter() *Iterator {
+	pairs := h.Pairs()
 
-		for _, pair := range h.Pairs {
-			if h.offset == idx {
-				h.offset++
-				return pair.Key, pair.Value, true
-			}
-			idx++
+	var offset int
+	return NewIterator(func() (Object, Object, bool) {
+		if offset >= len(pairs) {
+			return nil, nil, false
 		}
-	}
-
-	return nil, NewInteger(0), false
+		offset++
+		return pairs[offset-1].Key, pairs[offset-1].Value, true
+	})
 }
@@ -12,7 +12,51 @@ func TestHashObject(t *testing.T) {
 		{`{"a": 1} == {"a": 1, "b": 2}`, false},
 		{`{"a": 1} == {"b": 1}`, false},
 		{`{"a": 1} == {"a": "c"}`, false},
+		{`a = {}; a["a"] = a; b = {}; b["a"] = b; a == b`, true},
+		{`a = {}; a["a"] = a; a.to_s()`, `{"a": {...}}`},
+		{`{"b": 1, "a": 2, "c": 3}.to_s()`, `{"a": 2, "b": 1, "c": 3}`},
 		{`{{1: true}: "a"}.keys()`, `[{1: true}]`},
+		{`{1.0000001: "a", 1.0000002: "b"}[1.0000002]`, "b"},
+		{`{1.0000001: "a", 1.0000002: "b"}.keys().size()`, 2},
+		{`k = [1]; h = {}; h[k] = 1; k.yoink(2); h[[1]]`, 1},
+		{`k = [1]; h = {}; h[k] = 1; k.yoink(2); h.keys()`, `[[1]]`},
+		{`k = "ab"; h = {(k): 1}; k.reverse!(); h.keys()`, `["ab"]`},
+	}
+
+	testInput(t, tests)
+}
+
+func TestHashCollisions(t *testing.T) {
+	hashBytes := object.HashBytes
+	object.HashBytes = func([]byte) uint64 { return 0 }
+	defer func() { object.HashBytes = hashBytes }()
+
+	a, b := object.NewString("a"), object.NewString("b")
+	if a.HashKey() != b.HashKey() {
+		t.Fatalf("expected colliding hash keys")
+	}
+
+	h := object.NewHash([]object.HashPair{{Key: a, Value: object.NewInteger(1)}})
+	h.Set(b, object.NewInteger(2))
+	h.Set(object.NewString("a"), object.NewInteger(3))
+
+	if h.Len() != 2 {
+		t.Fatalf("wrong amount of pairs, got=%d", h.Len())
+	}
+	if value, ok := h.Get(a); !ok || value.Inspect() != "3" {
+		t.Errorf("wrong value for key a, got=%v", value)
+	}
+	if value, ok := h.Get(b); !ok || value.Inspect() != "2" {
+		t.Errorf("wrong value for key b, got=%v", value)
+	}
+	if _, ok := h.Get(object.NewString("c")); ok {
+		t.Errorf("expected no value for key c")
+	}
+
+	tests := []inputTestCase{
+		{`{"a": 1, "b": 2}["b"]`, 2},
+		{`{[1]: 1, [2]: 2, "x": 3}.keys().size()`, 3},
+		{`["a", "b", "a", "c", "b"].uniq()`, `["a", "b", "c"]`},
 	}
 
 	testInput(t, tests)
@@ -25,9 +69,14 @@ func TestHashObjectMethods(t *testing.T) {
 		{`({}.wat().lines().size() == {}.methods().size() + 1).plz_s()`, "true"},
 		{`{}.type()`, "HASH"},
 		{`a = {"a": "b", "b":"a"};b = []; foreach key, value in a { b.yoink(key) }; b.size()`, 2},
+		{`a = {"a": 1, "b": 2}; n = 0; foreach k in a { foreach j in a { n = n + 1 } }; n`, 4},
 		{`{"a": 1, "b": 2}["a"]`, 1},
 		{`{"a": 1, "b": 2}.keys().size()`, 2},
 		{`{"a": 1, "b": 2}.values().size()`, 2},
+		{`b = []; foreach key, value in {"a": 1} { b.yoink(key); b.yoink(value) }; b`, `["a", 1]`},
+		{`b = []; foreach key in {"a": 1} { b.yoink(key) }; b`, `["a"]`},
+		{`n = 0; {"a": 1, "b": 2}.each { |k, v| n = n + v }; n`, 3},
+		{`{"a": 1}.each { |k| k.nope() }`, "undefined method `.nope()` for STRING"},
 	}
 
 	testInput(t, tests)
@@ -0,0 +1,270 @@
+package object
+
+import "sort"
+
+// Heap is a binary heap returning its values smallest first, or largest
+// first for a max heap. Values are ordered by themselves or by the result
+// of the key function. Values of the same priority are returned in the
+// order they were pushed.
+type Heap struct {
+	Max bool
+	Key Object // FUNCTION or BUILTIN computing the priority, or nil
+
+	items  []heapItem
+	pushed int
+}
+
+type heapItem struct {
+	value    Object
+	priority Object
+	seq      int
+}
+
+func NewHeap(max bool, key Object) *Heap {
+	return &Heap{Max: max, Key: key}
+}
+
+func (h *Heap) Type() ObjectType { return HEAP_OBJ }
+func (h *Heap) Inspect() string {
+	return inspectObject(h, map[Object]bool{})
+}
+
+func (h *Heap) Len() int {
+	return len(h.items)
+}
+
+// less reports whether a is returned before b.
+func (h *Heap) less(a, b heapItem) (bool, *Error) {
+	c, err := compareOrder(a.priority, b.priority)
+	if err != nil {
+		return false, err
+	}
+	if h.Max {
+		c = -c
+	}
+	if c == 0 {
+		return a.seq < b.seq, nil
+	}
+	return c < 0, nil
+}
+
+// Push adds value. Values whose priority cannot be compared to the others
+// are not added and return an error.
+func (h *Heap) Push(value Object) Object {
+	item := heapItem{value: value, priority: value, seq: h.pushed}
+	if h.Key != nil {
+		item.priority = ApplyFunction(h.Key, []Object{value})
+		if IsError(item.priority) {
+			return item.priority
+		}
+	}
+
+	// find the position first, so the heap stays intact on errors
+	pos := len(h.items)
+	for pos > 0 {
+		parent := (pos - 1) / 2
+		less, err := h.less(item, h.items[parent])
+		if err != nil {
+			return err
+		}
+		if !less {
+			break
+		}
+		pos = parent
+	}
+
+	h.items = append(h.items, item)
+	for i := len(h.items) - 1; i > pos; {
+		parent := (i - 1) / 2
+		h.items[i] = h.items[parent]
+		i = parent
+	}
+	h.items[pos] = item
+	h.pushed++
+	return nil
+}
+
+// Peek returns the next value, or NULL if the heap is empty.
+func (h *Heap) Peek() Object {
+	if len(h.items) == 0 {
+		return NULL
+	}
+	return h.items[0].value
+}
+
+// Pop removes and returns the next value, or returns NULL if the heap is
+// empty.
+func (h *Heap) Pop() Object {
+	if len(h.items) == 0 {
+		return NULL
+	}
+
+	top := h.items[0].value
+	last := h.items[len(h.items)-1]
+	n := len(h.items) - 1
+
+	// follow the path the last item sinks down before moving anything
+	var path []int
+	for hole := 0; ; {
+		child := 2*hole + 1
+		if child >= n {
+			break
+		}
+		if right := child + 1; right < n {
+			if less, err := h.less(h.items[right], h.items[child]); err != nil {
+				return err
+			} else if less {
+				child = right
+			}
+		}
+		if less, err := h.less(h.items[child], last); err != nil {
+			return err
+		} else if !less {
+			break
+		}
+		path = append(path, child)
+		hole = child
+	}
+
+	pos := 0
+	for _, child := range path {
+		h.items[pos] = h.items[child]
+		pos = child
+	}
+	h.items[pos] = last
+	h.items[n] = heapItem{}
+	h.items = h.items[:n]
+	return top
+}
+
+// Sorted returns the values in the order they would be popped.
+func (h *Heap) Sorted() []Object {
+	items := make([]heapItem, len(h.items))
+	copy(items, h.items)
+
+	// all priorities were compared when they were pushed, errors can only
+	// come from arrays which differ in types further inside
+	sort.Slice(items, func(i, j int) bool {
+		less, _ := h.less(items[i], items[j])
+		return less
+	})
+
+	values := make([]Object, len(items))
+	for i, item := range items {
+		values[i] = item.value
+	}
+	return values
+}
+
+func init() {
+	objectMethods[HEAP_OBJ] = map[string]ObjectMethod{
+		"push": ObjectMethod{
+			description: "Adds the value to the heap and returns the heap. Returns an error if its priority cannot be compared to the ones of the other values.",
+			example: `🚀 > collections.heap().push(3).push(1)
+=> heap([1, 3])`,
+			argPattern: [][]string{
+				valueTypes,
+			},
+			returnPattern: [][]string{
+				[]string{HEAP_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				if err := o.(*Heap).Push(args[0]); err != nil {
+					return err
+				}
+				return o
+			},
+		},
+		"pop": ObjectMethod{
+			description: "Removes and returns the value with the lowest priority, or the highest for a max heap. Returns `null` if the heap is empty.",
+			example: `🚀 > h = collections.heap().push(3).push(1)
+🚀 > h.pop()
+=> 1
+🚀 > h
+=> heap([3])`,
+			returnPattern: [][]string{
+				valueTypes,
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Heap).Pop()
+			},
+		},
+		"peek": ObjectMethod{
+			description: "Returns the value `pop()` would return without removing it.",
+			example: `🚀 > collections.heap(:max).push(3).push(5).peek()
+=> 5`,
+			returnPattern: [][]string{
+				valueTypes,
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Heap).Peek()
+			},
+		},
+		"size": ObjectMethod{
+			description: "Returns the amount of values in the heap.",
+			example: `🚀 > collections.heap().push(1).push(1).size()
+=> 2`,
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewInteger(int64(o.(*Heap).Len()))
+			},
+		},
+		"empty?": ObjectMethod{
+			description: "Returns `true` if the heap has no values.",
+			example: `🚀 > collections.heap().empty?()
+=> true`,
+			returnPattern: [][]string{
+				[]string{BOOLEAN_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return nativeBool(o.(*Heap).Len() == 0)
+			},
+		},
+		"to_a": ObjectMethod{
+			description: "Returns the values in the order they would be popped, without removing them.",
+			example: `🚀 > collections.heap().push(2).push(1).push(3).to_a()
+=> [1, 2, 3]`,
+			returnPattern: [][]string{
+				[]string{ARRAY_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewArray(o.(*Heap).Sorted())
+			},
+		},
+		"iter": ObjectMethod{
+			description: "Returns a new iterator over the values in the order they would be popped, without removing them.",
+			example: `🚀 > collections.heap().push(2).push(1).iter().next()
+=> 1`,
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Heap).Iter()
+			},
+		},
+	}
+}
+
+func (h *Heap) InvokeMethod(method string, env Environment, args ...Object) Object {
+	return objectMethodLookup(h, method, args)
+}
+
+// Iter returns an iterator over a snapshot of the values in the order they
+// would be popped, and their positions.
+func (h *Heap) Iter() *Iterator {
+	return iterateObjects(h.Sorted())
+}
+
+// iterateObjects returns an iterator over values and their positions.
+func iterateObjects(values []Object) *Iterator {
+	var offset int
+	return NewIterator(func() (Object, Object, bool) {
+		if offset >= len(values) {
+			return nil, nil, false
+		}
+		offset++
+		return values[offset-1], NewInteger(int64(offset - 1)), true
+	})
+}
@@ -0,0 +1,61 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+func TestHeapObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`collections.heap().push(3).push(1).push(2).inspect()`, "heap([1, 2, 3])"},
+		{`collections.heap(:max).push(3).push(1).push(2).inspect()`, "heap(:max, [3, 2, 1])"},
+		{`collections.heap("max").push(1).push(2).peek()`, 2},
+		{`h = collections.heap().push(2).push(1); [h.pop(), h.pop(), h.pop()]`, "[1, 2, null]"},
+		{`collections.heap().push([2, "b"]).push([1, "z"]).push([2, "a"]).to_a()`, `[[1, "z"], [2, "a"], [2, "b"]]`},
+		{`collections.heap().push(1.5).push(1).push(2).to_a()`, "[1, 1.5, 2]"},
+		{`collections.heap().push("b").push("a").to_a()`, `["a", "b"]`},
+		{`collections.heap(-> (s) { s.size() }).push("ccc").push("a").push("bb").to_a()`, `["a", "bb", "ccc"]`},
+		{`collections.heap(:max, -> (s) { s.size() }).push("x").push("y").push("zz").to_a()`, `["zz", "x", "y"]`},
+		{`collections.heap().push(1).size()`, 1},
+		{`collections.heap().empty?()`, true},
+		{`collections.heap().peek()`, "null"},
+		{`h = collections.heap().push(2).push(1); h.iter().to_a() + [h.size()]`, "[1, 2, 2]"},
+		{`collections.heap().push(1).push("a")`, "cannot compare STRING with INTEGER"},
+		{`collections.heap(:nope)`, "unknown heap order :nope, want=:min|:max"},
+		{`collections.heap(:min, 1)`, "key of `collections.heap` must be FUNCTION|BUILTIN, got=INTEGER"},
+		{`collections.heap(:min, puts, puts)`, "wrong number of arguments to `collections.heap`. got=3, want=0..2"},
+	}
+
+	testInput(t, tests)
+}
+
+func TestHeapOrder(t *testing.T) {
+	h := object.NewHeap(false, nil)
+	values := []int64{5, 3, 9, 1, 7, 3, 8, 2, 6, 4, 0}
+	for _, value := range values {
+		if err := h.Push(object.NewInteger(value)); err != nil {
+			t.Fatalf("push failed: %s", err.Inspect())
+		}
+	}
+
+	var previous int64 = -1
+	for h.Len() > 0 {
+		value := h.Pop().(*object.Integer).Value
+		if value < previous {
+			t.Fatalf("values are not popped in order. got=%d after %d", value, previous)
+		}
+		previous = value
+	}
+	if h.Pop() != object.NULL {
+		t.Errorf("empty heap did not return NULL")
+	}
+
+	h.Push(object.NewInteger(1))
+	if err := h.Push(object.NewString("a")); err == nil {
+		t.Errorf("pushing an incomparable value did not fail")
+	}
+	if h.Len() != 1 || h.Inspect() != "heap([1])" {
+		t.Errorf("failed push changed the heap. got=%s", h.Inspect())
+	}
+}
@@ -0,0 +1,178 @@
+package object
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultPrettyWidth is the line width used by pp and pretty() if no width is
+// given.
+const DefaultPrettyWidth = 80
+
+// inspectObject returns the single line representation of o. Arrays and
+// hashes that contain themselves are printed as `[...]` and `{...}` on the
+// second visit.
+func inspectObject(o Object, visiting map[Object]bool) string {
+	switch o := o.(type) {
+	case *Array:
+		if visiting[o] {
+			return "[...]"
+		}
+		visiting[o] = true
+		defer delete(visiting, o)
+
+		elements := make([]string, len(o.Elements))
+		for i, element := range o.Elements {
+			elements[i] = inspectObject(element, visiting)
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *Hash:
+		if visiting[o] {
+			return "{...}"
+		}
+		visiting[o] = true
+		defer delete(visiting, o)
+
+		pairs := sortedPairs(o)
+		elements := make([]string, len(pairs))
+		for i, pair := range pairs {
+			elements[i] = inspectKey(pair.Key, visiting) + inspectObject(pair.Value, visiting)
+		}
+		return "{" + strings.Join(elements, ", ") + "}"
+	case *Set:
+		members := o.Elements()
+		elements := make([]string, len(members))
+		for i, element := range members {
+			elements[i] = inspectObject(element, visiting)
+		}
+		return "set([" + strings.Join(elements, ", ") + "])"
+	case *Heap:
+		if visiting[o] {
+			return "heap(...)"
+		}
+		visiting[o] = true
+		defer delete(visiting, o)
+
+		// a heap is printed in the order it would be popped
+		prefix := "heap("
+		if o.Max {
+			prefix = "heap(:max, "
+		}
+		return prefix + inspectObjects(o.Sorted(), visiting) + ")"
+	case *Deque:
+		if visiting[o] {
+			return "deque(...)"
+		}
+		visiting[o] = true
+		defer delete(visiting, o)
+
+		return "deque(" + inspectObjects(o.Values(), visiting) + ")"
+	case *Counter:
+		pairs := o.MostCommon()
+		elements := make([]string, len(pairs))
+		for i, pair := range pairs {
+			elements[i] = inspectKey(pair.Key, visiting) + pair.Value.Inspect()
+		}
+		return "counter({" + strings.Join(elements, ", ") + "})"
+	case *DefaultHash:
+		return "default_hash(" + inspectObject(o.Hash, visiting) + ")"
+	default:
+		return o.Inspect()
+	}
+}
+
+// inspectObjects returns values like an array.
+func inspectObjects(values []Object, visiting map[Object]bool) string {
+	elements := make([]string, len(values))
+	for i, value := range values {
+		elements[i] = inspectObject(value, visiting)
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// inspectKey returns the key of a hash pair followed by the colon. Symbols
+// are written like {name: 1}.
+func inspectKey(key Object, visiting map[Object]bool) string {
+	if symbol, ok := key.(*Symbol); ok && isSymbolName(symbol.Name) {
+		return symbol.Name + ": "
+	}
+	return inspectObject(key, visiting) + ": "
+}
+
+// Pretty returns the representation of o with nested arrays and hashes
+// spread over multiple indented lines where they don't fit into width.
+func Pretty(o Object, width int) string {
+	var out strings.Builder
+	prettyObject(&out, o, "", 0, width, map[Object]bool{})
+	return out.String()
+}
+
+// prettyObject writes o to out. indent is the indentation of the current
+// line and used is the amount of characters already written to it.
+func prettyObject(out *strings.Builder, o Object, indent string, used int, width int, visiting map[Object]bool) {
+	line := inspectObject(o, visiting)
+	if used+len(line) <= width || visiting[o] {
+		out.WriteString(line)
+		return
+	}
+
+	switch o := o.(type) {
+	case *Array:
+		if len(o.Elements) == 0 {
+			out.WriteString(line)
+			return
+		}
+		visiting[o] = true
+		defer delete(visiting, o)
+
+		inner := indent + "  "
+		out.WriteString("[\n")
+		for i, element := range o.Elements {
+			out.WriteString(inner)
+			prettyObject(out, element, inner, len(inner), width, visiting)
+			if i < len(o.Elements)-1 {
+				out.WriteString(",")
+			}
+			out.WriteString("\n")
+		}
+		out.WriteString(indent + "]")
+	case *Hash:
+		if o.Len() == 0 {
+			out.WriteString(line)
+			return
+		}
+		visiting[o] = true
+		defer delete(visiting, o)
+
+		inner := indent + "  "
+		pairs := sortedPairs(o)
+		out.WriteString("{\n")
+		for i, pair := range pairs {
+			key := inspectKey(pair.Key, visiting)
+			out.WriteString(inner + key)
+			prettyObject(out, pair.Value, inner, len(inner)+len(key), width, visiting)
+			if i < len(pairs)-1 {
+				out.WriteString(",")
+			}
+			out.WriteString("\n")
+		}
+		out.WriteString(indent + "}")
+	default:
+		out.WriteString(line)
+	}
+}
+
+// sortedPairs returns the pairs of h ordered by the representation of their
+// keys, so hashes with the same content are always printed the same way.
+func sortedPairs(h *Hash) []HashPair {
+	pairs := h.Pairs()
+	keys := make(map[Object]string, len(pairs))
+	for _, pair := range pairs {
+		keys[pair.Key] = inspectObject(pair.Key, map[Object]bool{})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return keys[pairs[i].Key] < keys[pairs[j].Key]
+	})
+	return pairs
+}
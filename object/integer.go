@@ -7,7 +7,6 @@ import (
 
 type Integer struct {
 	Value int64
-	index int64
 }
 
 func NewInteger(i int64) *Integer {
@@ -22,6 +21,17 @@ func (i *Integer) HashKey() HashKey {
 
 func init() {
 	objectMethods[INTEGER_OBJ] = map[string]ObjectMethod{
+		"iter": ObjectMethod{
+			description: "Returns a new iterator counting from 0 up to the integer.",
+			example: `🚀 > 3.iter().to_a()
+=> [0, 1, 2]`,
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Integer).Iter()
+			},
+		},
 		"plz_s": ObjectMethod{
 			description: "Returns a string representation of the integer. Also takes an argument which represents the integer base to convert between different number systems",
 			example: `🚀 > a = 456
@@ -90,15 +100,16 @@ func (i *Integer) ToFloat() Object {
 	return NewFloat(float64(i.Value))
 }
 
-func (i *Integer) Reset() {
-	i.index = 0
-}
-
-func (i *Integer) Next() (Object, Object, bool) {
-	if i.index < i.Value {
-		index := NewInteger(i.index)
-		i.index++
-		return index, index, true
-	}
-	return nil, NewInteger(0), false
+// Iter returns an iterator counting from 0 up to, but not including, the
+// integer.
+func (i *Integer) Iter() *Iterator {
+	var index int64
+	return NewIterator(func() (Object, Object, bool) {
+		if index >= i.Value {
+			return nil, nil, false
+		}
+		value := NewInteger(index)
+		index++
+		return value, value, true
+	})
 }
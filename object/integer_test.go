@@ -58,9 +58,10 @@ func TestIntegerInspect(t *testing.T) {
 
 func TestIntegerIteratable(t *testing.T) {
 	int1 := object.NewInteger(3)
+	iterator := int1.Iter()
 
 	for expected := int64(0); expected < 3; expected++ {
-		_, value, ok := int1.Next()
+		_, value, ok := iterator.Next()
 		actual := value.(*object.Integer)
 
 		if !ok {
@@ -76,14 +77,13 @@ func TestIntegerIteratable(t *testing.T) {
 		}
 	}
 
-	_, _, ok := int1.Next()
+	_, _, ok := iterator.Next()
 	if ok {
 		t.Errorf("integer iteration didn't finish")
 	}
 
-	int1.Reset()
-	_, _, ok = int1.Next()
+	_, _, ok = int1.Iter().Next()
 	if !ok {
-		t.Errorf("integer iteration shouldn't finish after reset")
+		t.Errorf("new integer iterator shouldn't be finished")
 	}
 }
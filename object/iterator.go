@@ -0,0 +1,371 @@
+package object
+
+// Iterator walks over a sequence of values. Every call of Iter() on an
+// Iterable returns a new Iterator, so iterating the same object in nested
+// loops is safe. Iterators are also created by Go code to produce lazy
+// sequences, see NewIterator.
+type Iterator struct {
+	next  func() (Object, Object, bool)
+	close func()
+
+	// value and key hold an element fetched by Done() but not yet returned
+	// by Next()
+	buffered bool
+	value    Object
+	key      Object
+	finished bool
+}
+
+// NewIterator returns an iterator calling next for every element. next
+// returns the value, the key (like the index of an array) and whether there
+// was an element at all. It is not called again once it returned false.
+func NewIterator(next func() (Object, Object, bool)) *Iterator {
+	return &Iterator{next: next}
+}
+
+// NewClosableIterator returns an iterator like NewIterator which calls close
+// once it is exhausted or closed early. Iterators holding resources, like a
+// running generator, use it to release them.
+func NewClosableIterator(next func() (Object, Object, bool), close func()) *Iterator {
+	return &Iterator{next: next, close: close}
+}
+
+func (it *Iterator) Type() ObjectType { return ITERATOR_OBJ }
+func (it *Iterator) Inspect() string  { return "<iterator>" }
+
+// Iter returns the iterator itself, so iterators can be used in foreach.
+func (it *Iterator) Iter() *Iterator { return it }
+
+// Next returns the next value and its key. ok is false once the iterator is
+// exhausted.
+func (it *Iterator) Next() (value Object, key Object, ok bool) {
+	if it.buffered {
+		it.buffered = false
+		return it.value, it.key, true
+	}
+	if it.finished {
+		return nil, nil, false
+	}
+
+	value, key, ok = it.next()
+	if !ok {
+		it.Close()
+	}
+	return value, key, ok
+}
+
+// Close stops the iterator. Following calls of Next return no elements.
+func (it *Iterator) Close() {
+	it.buffered = false
+	it.value = nil
+	it.key = nil
+	if it.finished {
+		return
+	}
+
+	it.finished = true
+	it.next = nil
+	if it.close != nil {
+		it.close()
+		it.close = nil
+	}
+}
+
+// Done reports whether the iterator is exhausted. To find out it may fetch
+// the next element, which is then returned by the following call of Next.
+func (it *Iterator) Done() bool {
+	if it.buffered {
+		return false
+	}
+	if it.finished {
+		return true
+	}
+
+	it.value, it.key, it.buffered = it.Next()
+	return !it.buffered
+}
+
+// Collect returns all values of the iterable, or the first error produced
+// while iterating.
+func Collect(iterable Iterable) ([]Object, Object) {
+	iterator := iterable.Iter()
+	defer iterator.Close()
+
+	values := []Object{}
+	for value, _, ok := iterator.Next(); ok; value, _, ok = iterator.Next() {
+		if IsError(value) {
+			return nil, value
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func init() {
+	objectMethods[ITERATOR_OBJ] = map[string]ObjectMethod{
+		"next": ObjectMethod{
+			description: "Returns the next value or `null` if the iterator is exhausted.",
+			example: `🚀 > it = [1, 2].iter()
+🚀 > it.next()
+=> 1
+🚀 > it.next()
+=> 2
+🚀 > it.next()
+=> null`,
+			returnPattern: [][]string{
+				[]string{STRING_OBJ, ARRAY_OBJ, HASH_OBJ, BOOLEAN_OBJ, INTEGER_OBJ, NULL_OBJ, FUNCTION_OBJ, FILE_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				value, _, ok := o.(*Iterator).Next()
+				if !ok {
+					return NULL
+				}
+				return value
+			},
+		},
+		"done?": ObjectMethod{
+			description: "Returns `true` if there are no more values.",
+			example: `🚀 > it = [1].iter()
+🚀 > it.done?()
+=> false
+🚀 > it.next()
+=> 1
+🚀 > it.done?()
+=> true`,
+			returnPattern: [][]string{
+				[]string{BOOLEAN_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				if o.(*Iterator).Done() {
+					return TRUE
+				}
+				return FALSE
+			},
+		},
+		"to_a": ObjectMethod{
+			description: "Returns all remaining values as array.",
+			example: `🚀 > "abc".iter().to_a()
+=> ["a", "b", "c"]`,
+			returnPattern: [][]string{
+				[]string{ARRAY_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				it := o.(*Iterator)
+				elements := []Object{}
+				for value, _, ok := it.Next(); ok; value, _, ok = it.Next() {
+					if IsError(value) {
+						return value
+					}
+					elements = append(elements, value)
+				}
+				return NewArray(elements)
+			},
+		},
+		"take": ObjectMethod{
+			description: "Returns a new iterator over the first n values.",
+			example: `🚀 > 10.iter().take(3).to_a()
+=> [0, 1, 2]`,
+			argPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return iteratorTake(o.(*Iterator), args[0].(*Integer).Value)
+			},
+		},
+		"drop": ObjectMethod{
+			description: "Returns a new iterator skipping the first n values.",
+			example: `🚀 > 5.iter().drop(3).to_a()
+=> [3, 4]`,
+			argPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return iteratorDrop(o.(*Iterator), args[0].(*Integer).Value)
+			},
+		},
+		"map": ObjectMethod{
+			description: "Returns a new iterator over the results of calling the function with every value.",
+			example: `🚀 > 3.iter().map(def(i) { return i * 2 }).to_a()
+=> [0, 2, 4]`,
+			argPattern: [][]string{
+				[]string{FUNCTION_OBJ, BUILTIN_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return iteratorMap(o.(*Iterator), args[0])
+			},
+		},
+		"select": ObjectMethod{
+			description: "Returns a new iterator over all values for which the function returns a truthy value.",
+			example: `🚀 > 6.iter().select(def(i) { return i % 2 == 0 }).to_a()
+=> [0, 2, 4]`,
+			argPattern: [][]string{
+				[]string{FUNCTION_OBJ, BUILTIN_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return iteratorSelect(o.(*Iterator), args[0])
+			},
+		},
+		"zip": ObjectMethod{
+			description: "Returns a new iterator over pairs of values of both iterators. Stops as soon as one of them is exhausted.",
+			example: `🚀 > 3.iter().zip(["a", "b"]).to_a()
+=> [[0, "a"], [1, "b"]]`,
+			argPattern: [][]string{
+				[]string{ITERATOR_OBJ, ARRAY_OBJ, HASH_OBJ, STRING_OBJ, INTEGER_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return iteratorZip(o.(*Iterator), args[0].(Iterable).Iter())
+			},
+		},
+		"chunk": ObjectMethod{
+			description: "Returns a new iterator over arrays of n values. The last array may be shorter.",
+			example: `🚀 > 5.iter().chunk(2).to_a()
+=> [[0, 1], [2, 3], [4]]`,
+			argPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				size := args[0].(*Integer).Value
+				if size < 1 {
+					return NewErrorFormat("chunk size must be positive, got %d", size)
+				}
+				return iteratorChunk(o.(*Iterator), size)
+			},
+		},
+	}
+}
+
+func (it *Iterator) InvokeMethod(method string, env Environment, args ...Object) Object {
+	return objectMethodLookup(it, method, args)
+}
+
+// The combinators below never read more values from their source than
+// needed and close it as soon as they are done with it.
+
+func iteratorTake(src *Iterator, n int64) *Iterator {
+	var taken int64
+	return NewClosableIterator(func() (Object, Object, bool) {
+		if taken >= n {
+			return nil, nil, false
+		}
+		taken++
+		return src.Next()
+	}, src.Close)
+}
+
+func iteratorDrop(src *Iterator, n int64) *Iterator {
+	return NewClosableIterator(func() (Object, Object, bool) {
+		for ; n > 0; n-- {
+			if _, _, ok := src.Next(); !ok {
+				return nil, nil, false
+			}
+		}
+		return src.Next()
+	}, src.Close)
+}
+
+func iteratorMap(src *Iterator, fn Object) *Iterator {
+	return NewClosableIterator(func() (Object, Object, bool) {
+		value, key, ok := src.Next()
+		if !ok || IsError(value) {
+			return value, key, ok
+		}
+		return ApplyFunction(fn, []Object{value}), key, true
+	}, src.Close)
+}
+
+func iteratorSelect(src *Iterator, fn Object) *Iterator {
+	return NewClosableIterator(func() (Object, Object, bool) {
+		for {
+			value, key, ok := src.Next()
+			if !ok || IsError(value) {
+				return value, key, ok
+			}
+
+			result := ApplyFunction(fn, []Object{value})
+			if IsError(result) {
+				return result, key, true
+			}
+			if IsTruthy(result) {
+				return value, key, true
+			}
+		}
+	}, src.Close)
+}
+
+func iteratorZip(src, other *Iterator) *Iterator {
+	var index int64
+	return NewClosableIterator(func() (Object, Object, bool) {
+		a, _, ok := src.Next()
+		if !ok {
+			return nil, nil, false
+		}
+		if IsError(a) {
+			return a, nil, true
+		}
+
+		b, _, ok := other.Next()
+		if !ok {
+			return nil, nil, false
+		}
+		if IsError(b) {
+			return b, nil, true
+		}
+
+		index++
+		return NewArrayWithObjects(a, b), NewInteger(index - 1), true
+	}, func() {
+		src.Close()
+		other.Close()
+	})
+}
+
+// maxChunkPrealloc limits the room reserved for a chunk up front, the size is
+// given by the user and most iterators end long before a huge chunk is full.
+const maxChunkPrealloc = 64
+
+func iteratorChunk(src *Iterator, size int64) *Iterator {
+	capacity := size
+	if capacity > maxChunkPrealloc {
+		capacity = maxChunkPrealloc
+	}
+
+	var index int64
+	return NewClosableIterator(func() (Object, Object, bool) {
+		chunk := make([]Object, 0, capacity)
+		for int64(len(chunk)) < size {
+			value, _, ok := src.Next()
+			if !ok {
+				break
+			}
+			if IsError(value) {
+				return value, nil, true
+			}
+			chunk = append(chunk, value)
+		}
+
+		if len(chunk) == 0 {
+			return nil, nil, false
+		}
+
+		index++
+		return NewArray(chunk), NewInteger(index - 1), true
+	}, src.Close)
+}
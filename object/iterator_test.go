@@ -0,0 +1,73 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+func TestIteratorObject(t *testing.T) {
+	values := []object.Object{object.NewInteger(1), object.NewInteger(2)}
+	var calls int
+	iterator := object.NewIterator(func() (object.Object, object.Object, bool) {
+		calls++
+		if calls > len(values) {
+			return nil, nil, false
+		}
+		return values[calls-1], object.NewInteger(int64(calls - 1)), true
+	})
+
+	if iterator.Type() != object.ITERATOR_OBJ {
+		t.Errorf("iterator.Type() returns wrong type")
+	}
+	if iterator.Done() {
+		t.Errorf("iterator should not be done")
+	}
+	if value, _, ok := iterator.Next(); !ok || value != values[0] {
+		t.Errorf("wrong first value, got=%v", value)
+	}
+	if value, _, ok := iterator.Next(); !ok || value != values[1] {
+		t.Errorf("wrong second value, got=%v", value)
+	}
+	if !iterator.Done() {
+		t.Errorf("iterator should be done")
+	}
+	if _, _, ok := iterator.Next(); ok {
+		t.Errorf("iterator should stay done")
+	}
+	if calls != 3 {
+		t.Errorf("next function should not be called after the end, got=%d calls", calls)
+	}
+}
+
+func TestIteratorObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`[1, 2].iter().next()`, 1},
+		{`it = [1, 2].iter(); it.next(); it.next(); it.next()`, "NULL"},
+		{`it = [1].iter(); it.done?()`, false},
+		{`it = [1].iter(); it.next(); it.done?()`, true},
+		{`it = [1, 2, 3].iter(); it.done?(); it.next()`, 1},
+		{`it = [1, 2, 3].iter(); it.next(); it.to_a()`, "[2, 3]"},
+		{`"abc".iter().to_a()`, `["a", "b", "c"]`},
+		{`3.iter().to_a()`, "[0, 1, 2]"},
+		{`{"a": 1}.iter().to_a()`, `["a"]`},
+		{`it = [1, 2, 3].iter(); it.next(); s = 0; foreach i in it { s = s + i }; s`, 5},
+		{`[].iter().type()`, "ITERATOR"},
+		{`10.iter().take(3).to_a()`, "[0, 1, 2]"},
+		{`2.iter().take(5).to_a()`, "[0, 1]"},
+		{`5.iter().drop(3).to_a()`, "[3, 4]"},
+		{`5.iter().drop(7).to_a()`, "[]"},
+		{`3.iter().map(def(i) { return i * 2 }).to_a()`, "[0, 2, 4]"},
+		{`3.iter().map(def(i) { return i + "a" }).to_a()`, "type mismatch: INTEGER + STRING"},
+		{`6.iter().select(def(i) { return i % 2 == 0 }).to_a()`, "[0, 2, 4]"},
+		{`3.iter().zip(["a", "b"]).to_a()`, `[[0, "a"], [1, "b"]]`},
+		{`5.iter().chunk(2).to_a()`, "[[0, 1], [2, 3], [4]]"},
+		{`5.iter().chunk(0)`, "chunk size must be positive, got 0"},
+		{`5.iter().chunk(4000000000).to_a()`, "[[0, 1, 2, 3, 4]]"},
+		{`5.iter().chunk(9223372036854775807).to_a()`, "[[0, 1, 2, 3, 4]]"},
+		{`100.iter().chunk(70).map(-> (c) { c.size() }).to_a()`, "[70, 30]"},
+		{`it = 10.iter(); it.take(2).to_a(); it.done?()`, true},
+	}
+
+	testInput(t, tests)
+}
@@ -16,3 +16,11 @@ func (m *Module) Inspect() string  { return fmt.Sprintf("module(%s)", m.Name) }
 func (m *Module) InvokeMethod(method string, env Environment, args ...Object) Object {
 	return objectMethodLookup(m, method, args)
 }
+
+// Member returns the exported attribute called name.
+func (m *Module) Member(name string) (Object, bool) {
+	if attributes, ok := m.Attributes.(*Hash); ok {
+		return attributes.Get(NewString(name))
+	}
+	return nil, false
+}
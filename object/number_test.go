@@ -37,6 +37,10 @@ func TestNumberObjects(t *testing.T) {
 		{"1/2", 0.5},
 		{"2/1", 2},
 
+		{"2**3", 8},
+		{"2**0", 1},
+		{"2**-1", 0.5},
+
 		// float | float
 		{"1.0 == 1.0", true},
 		{"1.0 == 2.0", false},
@@ -50,6 +54,11 @@ func TestNumberObjects(t *testing.T) {
 		{"2.0 > 1.0", true},
 		{"1.0 > 2.0", false},
 
+		{"-1.5", -1.5},
+		{"-(1.0 + 2.5)", -3.5},
+		{"2.0**2.0", 4.0},
+		{"4.0**0.5", 2.0},
+
 		{"0.0+0.0", 0.0},
 		{"0.0+1.0", 1.0},
 		{"1.0+2.0", 3.0},
@@ -13,15 +13,29 @@ type Object interface {
 	InvokeMethod(method string, env Environment, args ...Object) Object
 }
 
+// Iterable is implemented by all objects that can be used in foreach.
 type Iterable interface {
-	Reset()
-	Next() (Object, Object, bool)
+	Iter() *Iterator
 }
 
+// MemberHolder is implemented by objects whose members can be reached with
+// a dot, like the string keys of a hash or the attributes of a module.
+type MemberHolder interface {
+	Member(name string) (Object, bool)
+}
+
+// Hashable is implemented by all objects that can be used as hash keys.
 type Hashable interface {
+	Object
 	HashKey() HashKey
 }
 
+// Displayable is implemented by objects whose display representation, used
+// for output like puts, differs from the debug representation of Inspect.
+type Displayable interface {
+	Display() string
+}
+
 const (
 	INTEGER_OBJ      = "INTEGER"
 	FLOAT_OBJ        = "FLOAT"
@@ -31,11 +45,20 @@ const (
 	ERROR_OBJ        = "ERROR"
 	FUNCTION_OBJ     = "FUNCTION"
 	STRING_OBJ       = "STRING"
+	SYMBOL_OBJ       = "SYMBOL"
 	BUILTIN_OBJ      = "BUILTIN"
 	ARRAY_OBJ        = "ARRAY"
 	HASH_OBJ         = "HASH"
 	FILE_OBJ         = "FILE"
 	MODULE_OBJ       = "MODULE"
+	REGEX_OBJ        = "REGEX"
+	PROCESS_OBJ      = "PROCESS"
+	ITERATOR_OBJ     = "ITERATOR"
+	SET_OBJ          = "SET"
+	HEAP_OBJ         = "HEAP"
+	DEQUE_OBJ        = "DEQUE"
+	COUNTER_OBJ      = "COUNTER"
+	DEFAULT_HASH_OBJ = "DEFAULT_HASH"
 )
 
 type ObjectMethod struct {
@@ -136,6 +159,13 @@ func (om ObjectMethod) Call(o Object, args []Object) Object {
 
 var objectMethods = make(map[ObjectType]map[string]ObjectMethod)
 
+// ApplyFunction calls a FUNCTION or BUILTIN object with the given arguments.
+// It allows object methods and builtins to call back into rocket-lang code.
+// The evaluator imports this package, so it can't be called directly; it is
+// set in the init function of the evaluator package instead and is nil in a
+// program which doesn't link the evaluator.
+var ApplyFunction func(fn Object, args []Object) Object
+
 func ListObjectMethods() map[ObjectType]map[string]ObjectMethod {
 	return objectMethods
 }
@@ -179,6 +209,52 @@ func init() {
 				return NewString(fmt.Sprintf("%s supports the following methods:\n%s", o.Type(), strings.Join(result, "\n")))
 			},
 		},
+		"to_s": ObjectMethod{
+			description: "Returns the display representation of the object, which is also used by `puts`.",
+			example: `🚀 > "test".to_s()
+=> "test"
+🚀 > 1.5.to_s()
+=> "1.5"`,
+			returnPattern: [][]string{
+				[]string{STRING_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewString(Display(o))
+			},
+		},
+		"inspect": ObjectMethod{
+			description: "Returns the debug representation of the object, which is also shown by the REPL.",
+			example: `🚀 > puts("test".inspect())
+"test"`,
+			returnPattern: [][]string{
+				[]string{STRING_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewString(o.Inspect())
+			},
+		},
+		"pretty": ObjectMethod{
+			description: "Returns the inspect representation of the object. Nested arrays and hashes that don't fit into the line width (default 80) are spread over multiple indented lines.",
+			example: `🚀 > puts({"name": "rocket", "tags": ["a", "b"]}.pretty(20))
+{
+  "name": "rocket",
+  "tags": ["a", "b"]
+}`,
+			argsOptional: true,
+			argPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{STRING_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				width := DefaultPrettyWidth
+				if len(args) > 0 {
+					width = int(args[0].(*Integer).Value)
+				}
+				return NewString(Pretty(o, width))
+			},
+		},
 		"type": ObjectMethod{
 			description: "Returns the type of the object.",
 			example: `🚀 > "test".type()
@@ -209,7 +285,17 @@ func objectMethodLookup(o Object, method string, args []Object) Object {
 	return nil
 }
 
+// CompareObjects reports whether both objects have the same type and
+// content. Arrays and hashes are compared element by element, cyclic ones
+// included.
 func CompareObjects(ao, bo Object) bool {
+	return compareObjects(ao, bo, map[[2]Object]bool{})
+}
+
+// compareObjects keeps track of the containers that are already compared in
+// comparing. Meeting such a pair again means the comparison went around a
+// cycle, which does not add a difference.
+func compareObjects(ao, bo Object, comparing map[[2]Object]bool) bool {
 	switch ao.Type() {
 	case INTEGER_OBJ:
 		if b, ok := bo.(*Integer); ok {
@@ -226,6 +312,9 @@ func CompareObjects(ao, bo Object) bool {
 			return ao.(*Boolean).Value == b.Value
 		}
 		return false
+	case NULL_OBJ:
+		_, ok := bo.(*Null)
+		return ok
 	case ERROR_OBJ:
 		if b, ok := bo.(*Error); ok {
 			return ao.(*Error).Message == b.Message
@@ -236,16 +325,26 @@ func CompareObjects(ao, bo Object) bool {
 			return ao.(*String).Value == b.Value
 		}
 		return false
+	case SYMBOL_OBJ:
+		// symbols are interned
+		return ao == bo
 	case ARRAY_OBJ:
 		if b, ok := bo.(*Array); ok {
 			a, _ := ao.(*Array)
 
+			pair := [2]Object{a, b}
+			if comparing[pair] {
+				return true
+			}
+			comparing[pair] = true
+			defer delete(comparing, pair)
+
 			if len(a.Elements) != len(b.Elements) {
 				return false
 			}
 
 			for idx, element := range a.Elements {
-				if !CompareObjects(element, b.Elements[idx]) {
+				if !compareObjects(element, b.Elements[idx], comparing) {
 					return false
 				}
 			}
@@ -257,19 +356,23 @@ func CompareObjects(ao, bo Object) bool {
 		if b, ok := bo.(*Hash); ok {
 			a, _ := ao.(*Hash)
 
-			if len(a.Pairs) != len(b.Pairs) {
+			pair := [2]Object{a, b}
+			if comparing[pair] {
+				return true
+			}
+			comparing[pair] = true
+			defer delete(comparing, pair)
+
+			if a.Len() != b.Len() {
 				return false
 			}
 
-			for aKey, aPair := range a.Pairs {
-				bPair, ok := b.Pairs[aKey]
+			for _, aPair := range a.Pairs() {
+				bValue, ok := b.Get(aPair.Key)
 				if !ok {
 					return false
 				}
-				if !CompareObjects(aPair.Key, bPair.Key) {
-					return false
-				}
-				if !CompareObjects(aPair.Value, bPair.Value) {
+				if !compareObjects(aPair.Value, bValue, comparing) {
 					return false
 				}
 			}
@@ -277,11 +380,26 @@ func CompareObjects(ao, bo Object) bool {
 			return true
 		}
 		return false
+	case SET_OBJ:
+		if b, ok := bo.(*Set); ok {
+			a, _ := ao.(*Set)
+			return a.Len() == b.Len() && a.Subset(b)
+		}
+		return false
 	}
 
 	return false
 }
 
+// Display returns the representation of o meant for users, e.g. strings
+// without quotes. Objects without a special display form use Inspect.
+func Display(o Object) string {
+	if d, ok := o.(Displayable); ok {
+		return d.Display()
+	}
+	return o.Inspect()
+}
+
 func IsError(o Object) bool {
 	return o != nil && o.Type() == ERROR_OBJ
 }
@@ -18,8 +18,7 @@ type inputTestCase struct {
 
 func testEval(input string) object.Object {
 	l := lexer.New(input)
-	imports := make(map[string]struct{})
-	p := parser.New(l, imports)
+	p := parser.New(l, nil)
 	program, _ := p.ParseProgram()
 	env := object.NewEnvironment()
 
@@ -131,3 +130,78 @@ func TestIsFalsy(t *testing.T) {
 		t.Errorf("BOOLEAN_OBJ=false, should be falsy")
 	}
 }
+
+func TestDisplay(t *testing.T) {
+	tests := []struct {
+		obj      object.Object
+		expected string
+	}{
+		{object.NewString("a"), "a"},
+		{object.NewInteger(1), "1"},
+		{object.NULL, "null"},
+		{object.NewArrayWithObjects(object.NewString("a")), `["a"]`},
+	}
+
+	for _, tt := range tests {
+		if display := object.Display(tt.obj); display != tt.expected {
+			t.Errorf("wrong display representation. expected=%q, got=%q", tt.expected, display)
+		}
+	}
+}
+
+func TestDisplayMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`"a".to_s()`, "a"},
+		{`"a".inspect()`, `"a"`},
+		{`1.to_s()`, "1"},
+		{`[1, "a"].to_s()`, `[1, "a"]`},
+	}
+
+	testInput(t, tests)
+}
+
+func TestCyclicObjects(t *testing.T) {
+	a := object.NewArray(nil)
+	a.Elements = append(a.Elements, object.NewInteger(1), a)
+
+	if a.Inspect() != "[1, [...]]" {
+		t.Errorf("wrong inspect output for cyclic array, got=%s", a.Inspect())
+	}
+
+	h := object.NewHash(nil)
+	h.Set(object.NewString("self"), h)
+
+	if h.Inspect() != `{"self": {...}}` {
+		t.Errorf("wrong inspect output for cyclic hash, got=%s", h.Inspect())
+	}
+
+	b := object.NewArray(nil)
+	b.Elements = append(b.Elements, object.NewInteger(1), b)
+
+	if a.HashKey() != b.HashKey() {
+		t.Errorf("equal cyclic arrays should have the same hash key")
+	}
+	if !object.CompareObjects(a, b) {
+		t.Errorf("equal cyclic arrays should be equal")
+	}
+
+	c := object.NewArray(nil)
+	c.Elements = append(c.Elements, object.NewInteger(2), c)
+	if object.CompareObjects(a, c) {
+		t.Errorf("different cyclic arrays should not be equal")
+	}
+}
+
+func TestPretty(t *testing.T) {
+	tests := []inputTestCase{
+		{`[1, 2].pretty()`, "[1, 2]"},
+		{`{"b": 1, "a": 2}.pretty()`, `{"a": 2, "b": 1}`},
+		{`{"name": "rocket", "tags": ["a", "b"]}.pretty(20)`, "{\n  \"name\": \"rocket\",\n  \"tags\": [\"a\", \"b\"]\n}"},
+		{`[[1, 2], [3, 4]].pretty(10)`, "[\n  [1, 2],\n  [3, 4]\n]"},
+		{`{"a": [1, 2, 3]}.pretty(10)`, "{\n  \"a\": [\n    1,\n    2,\n    3\n  ]\n}"},
+		{`a = [1]; a.yoink(a); a.pretty(5)`, "[\n  1,\n  [...]\n]"},
+		{`"a".pretty()`, `"a"`},
+	}
+
+	testInput(t, tests)
+}
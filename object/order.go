@@ -0,0 +1,48 @@
+package object
+
+import "strings"
+
+// compareOrder returns -1, 0 or 1 if a is less than, equal to or greater
+// than b. Numbers are compared by value, strings byte by byte and arrays
+// element by element, so arrays like [priority, item] can be ordered.
+// Other objects have no order and return an error.
+func compareOrder(a, b Object) (int, *Error) {
+	switch {
+	case IsNumber(a) && IsNumber(b):
+		if a, ok := a.(*Integer); ok {
+			if b, ok := b.(*Integer); ok {
+				return compareValues(a.Value < b.Value, a.Value > b.Value), nil
+			}
+		}
+		x, y := toFloat(a), toFloat(b)
+		return compareValues(x < y, x > y), nil
+	case a.Type() == STRING_OBJ && b.Type() == STRING_OBJ:
+		return strings.Compare(a.(*String).Value, b.(*String).Value), nil
+	case a.Type() == ARRAY_OBJ && b.Type() == ARRAY_OBJ:
+		x, y := a.(*Array).Elements, b.(*Array).Elements
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c, err := compareOrder(x[i], y[i]); err != nil || c != 0 {
+				return c, err
+			}
+		}
+		return compareValues(len(x) < len(y), len(x) > len(y)), nil
+	}
+	return 0, NewErrorFormat("cannot compare %s with %s", a.Type(), b.Type())
+}
+
+func compareValues(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(o Object) float64 {
+	if i, ok := o.(*Integer); ok {
+		return float64(i.Value)
+	}
+	return o.(*Float).Value
+}
@@ -0,0 +1,117 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// Process is a running subprocess started with `spawn`. Its standard streams
+// are exposed as files backed by pipes, streams that were not piped are nil.
+type Process struct {
+	Cmd    *exec.Cmd
+	Stdin  *File
+	Stdout *File
+	Stderr *File
+
+	exitCode *Integer
+}
+
+func NewProcess(cmd *exec.Cmd, stdin, stdout, stderr *File) *Process {
+	return &Process{Cmd: cmd, Stdin: stdin, Stdout: stdout, Stderr: stderr}
+}
+
+func (p *Process) Type() ObjectType { return PROCESS_OBJ }
+func (p *Process) Inspect() string {
+	return fmt.Sprintf("<process:%d>", p.Cmd.Process.Pid)
+}
+
+// Wait closes the stdin pipe of the process and waits for it to exit. It
+// returns the exit code and can be called multiple times.
+func (p *Process) Wait() Object {
+	if p.exitCode != nil {
+		return p.exitCode
+	}
+
+	if p.Stdin != nil && p.Stdin.Position != -1 {
+		p.Stdin.Handle.Close()
+		p.Stdin.Position = -1
+	}
+
+	err := p.Cmd.Wait()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return NewError(err)
+	}
+
+	p.exitCode = NewInteger(int64(p.Cmd.ProcessState.ExitCode()))
+	return p.exitCode
+}
+
+func init() {
+	objectMethods[PROCESS_OBJ] = map[string]ObjectMethod{
+		"pid": ObjectMethod{
+			description: "Returns the process id.",
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewInteger(int64(o.(*Process).Cmd.Process.Pid))
+			},
+		},
+		"stdin": ObjectMethod{
+			description: "Returns the writable stdin pipe of the process or `null` if stdin was given as string.",
+			example: `🚀 > p = spawn(["cat"])
+🚀 > p.stdin().write("hello")
+=> true`,
+			returnPattern: [][]string{
+				[]string{FILE_OBJ, NULL_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return fileOrNull(o.(*Process).Stdin)
+			},
+		},
+		"stdout": ObjectMethod{
+			description: "Returns the readable stdout pipe of the process.",
+			example: `🚀 > spawn(["echo", "hello"]).stdout().content()
+=> "hello\n"`,
+			returnPattern: [][]string{
+				[]string{FILE_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return fileOrNull(o.(*Process).Stdout)
+			},
+		},
+		"stderr": ObjectMethod{
+			description: "Returns the readable stderr pipe of the process.",
+			returnPattern: [][]string{
+				[]string{FILE_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return fileOrNull(o.(*Process).Stderr)
+			},
+		},
+		"wait": ObjectMethod{
+			description: "Closes stdin, waits for the process to exit and returns its exit code. Read stdout and stderr before, otherwise a process writing a lot of output blocks forever.",
+			example: `🚀 > spawn(["false"]).wait()
+=> 1`,
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Process).Wait()
+			},
+		},
+	}
+}
+
+func (p *Process) InvokeMethod(method string, env Environment, args ...Object) Object {
+	return objectMethodLookup(p, method, args)
+}
+
+func fileOrNull(f *File) Object {
+	if f == nil {
+		return NULL
+	}
+	return f
+}
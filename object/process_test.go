@@ -0,0 +1,19 @@
+package object_test
+
+import (
+	"testing"
+)
+
+func TestProcessObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`spawn(["echo", "hello"]).stdout().content()`, "hello\n"},
+		{`spawn(["sh", "-c", "echo oops >&2"]).stderr().content()`, "oops\n"},
+		{`p = spawn(["cat"]); p.stdin().write("ping"); p.stdin().close(); p.stdout().content()`, "ping"},
+		{`p = spawn(["sh", "-c", "exit 4"]); p.wait(); p.wait()`, 4},
+		{`spawn(["cat"], {"stdin": "x"}).stdin()`, "NULL"},
+		{`spawn(["true"]).pid() > 0`, true},
+		{`spawn(["true"]).type()`, "PROCESS"},
+	}
+
+	testInput(t, tests)
+}
@@ -0,0 +1,105 @@
+package object
+
+import (
+	"regexp"
+)
+
+type Regex struct {
+	Regexp *regexp.Regexp
+}
+
+func NewRegex(pattern string) (*Regex, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Regex{Regexp: re}, nil
+}
+
+func (r *Regex) Type() ObjectType { return REGEX_OBJ }
+func (r *Regex) Inspect() string  { return "/" + r.Regexp.String() + "/" }
+
+func init() {
+	objectMethods[REGEX_OBJ] = map[string]ObjectMethod{
+		"match?": ObjectMethod{
+			description: "Returns `true` if the regex matches the given string.",
+			example: `🚀 > regex("[0-9]+").match?("abc123")
+=> true`,
+			argPattern: [][]string{
+				[]string{STRING_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{BOOLEAN_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				r := o.(*Regex)
+				if r.Regexp.MatchString(args[0].(*String).Value) {
+					return TRUE
+				}
+				return FALSE
+			},
+		},
+		"match": ObjectMethod{
+			description: "Matches the regex against the given string. Shorthand for `string.match(regex)`.",
+			example: `🚀 > regex("(?P<num>[0-9]+)").match("abc123")
+=> {0: "123", 1: "123", "num": "123"}`,
+			argPattern: [][]string{
+				[]string{STRING_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{HASH_OBJ, NULL_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return regexMatch(o.(*Regex), args[0].(*String).Value)
+			},
+		},
+		"source": ObjectMethod{
+			description: "Returns the pattern the regex was created from.",
+			example: `🚀 > regex("[a-z]+").source()
+=> "[a-z]+"`,
+			returnPattern: [][]string{
+				[]string{STRING_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewString(o.(*Regex).Regexp.String())
+			},
+		},
+	}
+}
+
+func (r *Regex) InvokeMethod(method string, env Environment, args ...Object) Object {
+	return objectMethodLookup(r, method, args)
+}
+
+// regexMatch returns the first match of the regex in s as hash or NULL if
+// there is none.
+func regexMatch(r *Regex, s string) Object {
+	loc := r.Regexp.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return NULL
+	}
+	return newMatchHash(r.Regexp, s, loc)
+}
+
+// newMatchHash builds a hash of all capture groups of a single match. Every
+// group is available by its number, named groups additionally by their name.
+// Groups that did not participate in the match are NULL.
+func newMatchHash(re *regexp.Regexp, s string, loc []int) *Hash {
+	hash := NewHash(nil)
+	names := re.SubexpNames()
+
+	for i := 0; i < len(loc)/2; i++ {
+		var value Object = NULL
+		if loc[2*i] >= 0 {
+			value = NewString(s[loc[2*i]:loc[2*i+1]])
+		}
+
+		hash.Set(NewInteger(int64(i)), value)
+
+		if names[i] != "" {
+			hash.Set(NewString(names[i]), value)
+		}
+	}
+
+	return hash
+}
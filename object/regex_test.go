@@ -0,0 +1,42 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+func TestRegexObject(t *testing.T) {
+	re, err := object.NewRegex("[a-z]+")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if re.Type() != object.REGEX_OBJ {
+		t.Errorf("regex.Type() returns wrong type")
+	}
+	if re.Inspect() != "/[a-z]+/" {
+		t.Errorf("wrong inspect output, got=%s", re.Inspect())
+	}
+
+	if _, err := object.NewRegex("("); err == nil {
+		t.Errorf("expected an error for an invalid pattern")
+	}
+}
+
+func TestRegexObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`regex("[0-9]+").match?("abc123")`, true},
+		{`regex("[0-9]+").match?("abc")`, false},
+		{`regex("[0-9]+").source()`, "[0-9]+"},
+		{`regex("([0-9]+)").match("abc123")[1]`, "123"},
+		{`regex("[0-9]+").match("abc")`, "NULL"},
+		{`regex("[0-9]+").type()`, "REGEX"},
+		{`regex("(")`, "error parsing regexp: missing closing ): `(`"},
+		{`"abc" =~ regex("[0-9]")`, "NULL"},
+		{`"abc1" =~ regex("[0-9]")`, 3},
+		{`regex(1)`, "argument to `regex` must be STRING, got=INTEGER"},
+		{`(regex("a").wat().lines().size() == regex("a").methods().size() + 1).plz_s()`, "true"},
+	}
+
+	testInput(t, tests)
+}
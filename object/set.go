@@ -0,0 +1,378 @@
+package object
+
+// Set holds distinct elements in the order they were added. Elements must
+// be hashable, they are looked up in a hash, so membership is checked in
+// constant time. Like hash keys, mutable elements are copied when added.
+type Set struct {
+	// positions maps every element to its position in elements
+	positions *Hash
+
+	// elements are in insertion order, deleted ones are nil until there
+	// are enough of them to compact the slice
+	elements []Hashable
+	deleted  int
+}
+
+// NewSet returns a set of the given elements.
+func NewSet(elements []Hashable) *Set {
+	s := &Set{positions: NewHash(nil)}
+	for _, element := range elements {
+		s.Add(element)
+	}
+	return s
+}
+
+func (s *Set) Type() ObjectType { return SET_OBJ }
+func (s *Set) Inspect() string {
+	return inspectObject(s, map[Object]bool{})
+}
+
+// HashKey combines the hash keys of the elements independent of their
+// order, equal sets added in a different order have the same hash key.
+func (s *Set) HashKey() HashKey {
+	var value uint64
+	for _, element := range s.elements {
+		if element != nil {
+			value += element.HashKey().Value
+		}
+	}
+	return HashKey{Type: s.Type(), Value: value}
+}
+
+// Add adds element and reports whether it was not in the set before.
+func (s *Set) Add(element Hashable) bool {
+	if s.Include(element) {
+		return false
+	}
+
+	element = copyKey(element, map[Object]Object{}).(Hashable)
+	s.positions.Set(element, NewInteger(int64(len(s.elements))))
+	s.elements = append(s.elements, element)
+	return true
+}
+
+// Delete removes element and reports whether it was in the set.
+func (s *Set) Delete(element Hashable) bool {
+	position, ok := s.positions.Get(element)
+	if !ok {
+		return false
+	}
+
+	s.positions.Delete(element)
+	s.elements[position.(*Integer).Value] = nil
+	s.deleted++
+
+	if s.deleted > len(s.elements)/2 {
+		s.compact()
+	}
+	return true
+}
+
+// compact removes the deleted elements from the slice.
+func (s *Set) compact() {
+	s.elements = s.Elements()
+	s.deleted = 0
+	for i, element := range s.elements {
+		s.positions.Set(element, NewInteger(int64(i)))
+	}
+}
+
+// Include reports whether element is in the set.
+func (s *Set) Include(element Hashable) bool {
+	_, ok := s.positions.Get(element)
+	return ok
+}
+
+// Len returns the amount of elements.
+func (s *Set) Len() int {
+	return len(s.elements) - s.deleted
+}
+
+// Elements returns the elements in insertion order.
+func (s *Set) Elements() []Hashable {
+	elements := make([]Hashable, 0, s.Len())
+	for _, element := range s.elements {
+		if element != nil {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// Union returns a set of the elements of s followed by the ones of other.
+func (s *Set) Union(other *Set) *Set {
+	result := NewSet(s.Elements())
+	for _, element := range other.Elements() {
+		result.Add(element)
+	}
+	return result
+}
+
+// Intersection returns a set of the elements of s which are in other.
+func (s *Set) Intersection(other *Set) *Set {
+	result := NewSet(nil)
+	for _, element := range s.Elements() {
+		if other.Include(element) {
+			result.Add(element)
+		}
+	}
+	return result
+}
+
+// Difference returns a set of the elements of s which are not in other.
+func (s *Set) Difference(other *Set) *Set {
+	result := NewSet(nil)
+	for _, element := range s.Elements() {
+		if !other.Include(element) {
+			result.Add(element)
+		}
+	}
+	return result
+}
+
+// Subset reports whether all elements of s are in other.
+func (s *Set) Subset(other *Set) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for _, element := range s.Elements() {
+		if !other.Include(element) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToSet returns the elements of an array or set as a set. Other objects
+// and elements which are not hashable return an error.
+func ToSet(o Object) (*Set, *Error) {
+	switch o := o.(type) {
+	case *Set:
+		return o, nil
+	case *Array:
+		elements := make([]Hashable, len(o.Elements))
+		for i, element := range o.Elements {
+			hashable, ok := element.(Hashable)
+			if !ok {
+				return nil, NewErrorFormat("unusable as set element: %s", element.Type())
+			}
+			elements[i] = hashable
+		}
+		return NewSet(elements), nil
+	}
+	return nil, NewErrorFormat("cannot convert %s to SET", o.Type())
+}
+
+// hashableTypes are the types of objects which can be hash keys or set
+// elements.
+var hashableTypes = []string{STRING_OBJ, SYMBOL_OBJ, INTEGER_OBJ, FLOAT_OBJ, BOOLEAN_OBJ, ARRAY_OBJ, HASH_OBJ, SET_OBJ}
+
+// valueTypes are the types of all objects which can be stored in a variable.
+var valueTypes = []string{
+	STRING_OBJ, SYMBOL_OBJ, INTEGER_OBJ, FLOAT_OBJ, BOOLEAN_OBJ, NULL_OBJ, ARRAY_OBJ, HASH_OBJ, SET_OBJ,
+	FUNCTION_OBJ, BUILTIN_OBJ, FILE_OBJ, MODULE_OBJ, REGEX_OBJ, PROCESS_OBJ, ITERATOR_OBJ,
+	HEAP_OBJ, DEQUE_OBJ, COUNTER_OBJ, DEFAULT_HASH_OBJ,
+}
+
+func init() {
+	objectMethods[SET_OBJ] = map[string]ObjectMethod{
+		"add": ObjectMethod{
+			description: "Adds the element to the set and returns the set.",
+			example: `🚀 > set([1]).add(2).add(1)
+=> set([1, 2])`,
+			argPattern: [][]string{
+				hashableTypes,
+			},
+			returnPattern: [][]string{
+				[]string{SET_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				s := o.(*Set)
+				s.Add(args[0].(Hashable))
+				return s
+			},
+		},
+		"delete": ObjectMethod{
+			description: "Removes the element from the set. Returns `true` if it was in the set.",
+			example: `🚀 > s = set([1, 2])
+🚀 > s.delete(1)
+=> true
+🚀 > s
+=> set([2])`,
+			argPattern: [][]string{
+				hashableTypes,
+			},
+			returnPattern: [][]string{
+				[]string{BOOLEAN_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return nativeBool(o.(*Set).Delete(args[0].(Hashable)))
+			},
+		},
+		"include?": ObjectMethod{
+			description: "Returns `true` if the element is in the set.",
+			example: `🚀 > set(["a", "b"]).include?("a")
+=> true`,
+			argPattern: [][]string{
+				hashableTypes,
+			},
+			returnPattern: [][]string{
+				[]string{BOOLEAN_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return nativeBool(o.(*Set).Include(args[0].(Hashable)))
+			},
+		},
+		"union": ObjectMethod{
+			description: "Returns a new set with the elements of both sets. Same as `a | b`.",
+			example: `🚀 > set([1, 2]).union(set([2, 3]))
+=> set([1, 2, 3])`,
+			argPattern: [][]string{
+				[]string{SET_OBJ, ARRAY_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{SET_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				other, err := ToSet(args[0])
+				if err != nil {
+					return err
+				}
+				return o.(*Set).Union(other)
+			},
+		},
+		"intersection": ObjectMethod{
+			description: "Returns a new set with the elements which are in both sets. Same as `a & b`.",
+			example: `🚀 > set([1, 2]).intersection(set([2, 3]))
+=> set([2])`,
+			argPattern: [][]string{
+				[]string{SET_OBJ, ARRAY_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{SET_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				other, err := ToSet(args[0])
+				if err != nil {
+					return err
+				}
+				return o.(*Set).Intersection(other)
+			},
+		},
+		"difference": ObjectMethod{
+			description: "Returns a new set with the elements which are not in the other set. Same as `a - b`.",
+			example: `🚀 > set([1, 2]).difference(set([2, 3]))
+=> set([1])`,
+			argPattern: [][]string{
+				[]string{SET_OBJ, ARRAY_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{SET_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				other, err := ToSet(args[0])
+				if err != nil {
+					return err
+				}
+				return o.(*Set).Difference(other)
+			},
+		},
+		"subset?": ObjectMethod{
+			description: "Returns `true` if all elements of the set are in the other set.",
+			example: `🚀 > set([1]).subset?(set([1, 2]))
+=> true`,
+			argPattern: [][]string{
+				[]string{SET_OBJ, ARRAY_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{BOOLEAN_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				other, err := ToSet(args[0])
+				if err != nil {
+					return err
+				}
+				return nativeBool(o.(*Set).Subset(other))
+			},
+		},
+		"size": ObjectMethod{
+			description: "Returns the amount of elements in the set.",
+			example: `🚀 > set([1, 1, 2]).size()
+=> 2`,
+			returnPattern: [][]string{
+				[]string{INTEGER_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewInteger(int64(o.(*Set).Len()))
+			},
+		},
+		"to_a": ObjectMethod{
+			description: "Returns the elements of the set in insertion order.",
+			example: `🚀 > set([2, 1, 2]).to_a()
+=> [2, 1]`,
+			returnPattern: [][]string{
+				[]string{ARRAY_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				elements := o.(*Set).Elements()
+				result := make([]Object, len(elements))
+				for i, element := range elements {
+					result[i] = element
+				}
+				return NewArray(result)
+			},
+		},
+		"iter": ObjectMethod{
+			description: "Returns a new iterator over the elements of the set in insertion order.",
+			example: `🚀 > set([2, 1]).iter().next()
+=> 2`,
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*Set).Iter()
+			},
+		},
+		"each": ObjectMethod{
+			description: "Calls the function with every element in insertion order and returns the set. Stops at the first error.",
+			example: `🚀 > set([1, 2]).each { |x| puts(x) }
+1
+2
+=> set([1, 2])`,
+			argPattern: [][]string{
+				[]string{FUNCTION_OBJ, BUILTIN_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{SET_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				s := o.(*Set)
+				for _, element := range s.Elements() {
+					if result := ApplyFunction(args[0], []Object{element}); IsError(result) {
+						return result
+					}
+				}
+				return s
+			},
+		},
+	}
+}
+
+func (s *Set) InvokeMethod(method string, env Environment, args ...Object) Object {
+	return objectMethodLookup(s, method, args)
+}
+
+// Iter returns an iterator over the elements in insertion order and their
+// positions. Elements added while iterating are not visited.
+func (s *Set) Iter() *Iterator {
+	elements := s.Elements()
+
+	var offset int
+	return NewIterator(func() (Object, Object, bool) {
+		if offset >= len(elements) {
+			return nil, nil, false
+		}
+		offset++
+		return elements[offset-1], NewInteger(int64(offset - 1)), true
+	})
+}
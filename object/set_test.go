@@ -0,0 +1,67 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+func TestSetObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{`set([3, 1, 3, "a"]).inspect()`, `set([3, 1, "a"])`},
+		{`set().inspect()`, `set([])`},
+		{`set("abca").inspect()`, `set(["a", "b", "c"])`},
+		{`set({"a": 1}).inspect()`, `set(["a"])`},
+		{`set([1]).add(2).add(1).inspect()`, `set([1, 2])`},
+		{`s = set([1, 2]); [s.delete(1), s.delete(1), s.to_a()]`, "[true, false, [2]]"},
+		{`s = set([1, 2, 3]); s.delete(1); s.delete(2); s.add(1); s.to_a()`, "[3, 1]"},
+		{`set([[1], {"a": 1}]).include?([1])`, true},
+		{`set([[1], {"a": 1}]).include?({"a": 1})`, true},
+		{`set([1]).include?("1")`, false},
+		{`set([1, 1, 2]).size()`, 2},
+		{`set([1, 2]).union(set([2, 3])).to_a()`, "[1, 2, 3]"},
+		{`set([1, 2]).union([3]).to_a()`, "[1, 2, 3]"},
+		{`set([1, 2]).intersection(set([2, 3])).to_a()`, "[2]"},
+		{`set([1, 2]).difference(set([2, 3])).to_a()`, "[1]"},
+		{`set([1]).subset?(set([1, 2]))`, true},
+		{`set([1, 3]).subset?([1, 2])`, false},
+		{`set([2, 1]).iter().to_a()`, "[2, 1]"},
+		{`a = []; set([2, 1]).each { |x| a.yoink(x) }; a`, "[2, 1]"},
+		{`set([1, 2]) == set([2, 1])`, true},
+		{`set([1, 2]) == set([1])`, false},
+		{`{set([1, 2]): "x"}[set([2, 1])]`, "x"},
+		{`a = [1]; s = set([a]); a.yoink(2); s.to_a()`, "[[1]]"},
+		{`set([-> { 1 }])`, "unusable as set element: FUNCTION"},
+		{`set(1.5)`, "argument 1 to `set` must be iterable, got=FLOAT"},
+		{`set([1], [2])`, "wrong number of arguments to `set`. got=2, want=0..1"},
+		{`set([1]).union(1)`, "wrong argument type on position 0: got=INTEGER, want=SET|ARRAY"},
+	}
+
+	testInput(t, tests)
+}
+
+func TestSetInsertionOrder(t *testing.T) {
+	s := object.NewSet(nil)
+	for i := 0; i < 10; i++ {
+		s.Add(object.NewInteger(int64(i)))
+	}
+	for i := 0; i < 8; i++ {
+		s.Delete(object.NewInteger(int64(i)))
+	}
+	s.Add(object.NewInteger(0))
+
+	if s.Len() != 3 {
+		t.Fatalf("wrong size. expected=3, got=%d", s.Len())
+	}
+	if s.Inspect() != "set([8, 9, 0])" {
+		t.Errorf("wrong order. got=%s", s.Inspect())
+	}
+	for _, i := range []int64{8, 9, 0} {
+		if !s.Include(object.NewInteger(i)) {
+			t.Errorf("%d is missing", i)
+		}
+	}
+	if s.HashKey() != object.NewSet([]object.Hashable{object.NewInteger(0), object.NewInteger(9), object.NewInteger(8)}).HashKey() {
+		t.Errorf("equal sets have different hash keys")
+	}
+}
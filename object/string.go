@@ -1,15 +1,13 @@
 package object
 
 import (
-	"hash/fnv"
 	"strconv"
 	"strings"
 	"unicode/utf8"
 )
 
 type String struct {
-	Value  string
-	offset int
+	Value string
 }
 
 func NewString(s string) *String {
@@ -18,6 +16,17 @@ func NewString(s string) *String {
 
 func init() {
 	objectMethods[STRING_OBJ] = map[string]ObjectMethod{
+		"iter": ObjectMethod{
+			description: "Returns a new iterator over the characters of the string.",
+			example: `🚀 > "ab".iter().to_a()
+=> ["a", "b"]`,
+			returnPattern: [][]string{
+				[]string{ITERATOR_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return o.(*String).Iter()
+			},
+		},
 		"count": ObjectMethod{
 			description: "Counts how often a given string or integer occurs in the string. Converts given integers to strings automatically.",
 			example: `🚀 > "test".count("t")
@@ -110,6 +119,17 @@ func init() {
 				return NewInteger(i)
 			},
 		},
+		"to_sym": ObjectMethod{
+			description: "Returns the symbol with the string as name.",
+			example: `🚀 > "name".to_sym()
+=> :name`,
+			returnPattern: [][]string{
+				[]string{SYMBOL_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewSymbol(o.(*String).Value)
+			},
+		},
 		"replace": ObjectMethod{
 			description: "Replaces the first string with the second string in the given string.",
 			example: `🚀 > "test".replace("t", "f")
@@ -170,15 +190,18 @@ func init() {
 			},
 		},
 		"split": ObjectMethod{
-			description: "Splits the string on a given seperator and returns all the chunks in an array. Default seperator is `\" \"`",
+			description: "Splits the string on a given seperator and returns all the chunks in an array. The seperator can be a string or a regex. Default seperator is `\" \"`",
 			example: `🚀 > "a,b,c,d".split(",")
 => ["a", "b", "c", "d"]
 
 🚀 > "test and another test".split()
-=> ["test", "and", "another", "test"]`,
+=> ["test", "and", "another", "test"]
+
+🚀 > "a1b22c".split(regex("[0-9]+"))
+=> ["a", "b", "c"]`,
 			argsOptional: true,
 			argPattern: [][]string{
-				[]string{STRING_OBJ},
+				[]string{STRING_OBJ, REGEX_OBJ},
 			},
 			returnPattern: [][]string{
 				[]string{ARRAY_OBJ},
@@ -188,7 +211,12 @@ func init() {
 				sep := " "
 
 				if len(args) > 0 {
-					sep = args[0].(*String).Value
+					switch arg := args[0].(type) {
+					case *String:
+						sep = arg.Value
+					case *Regex:
+						return NewStringArray(arg.Regexp.Split(s.Value, -1))
+					}
 				}
 
 				fields := strings.Split(s.Value, sep)
@@ -201,6 +229,117 @@ func init() {
 				return NewArray(result)
 			},
 		},
+		"match": ObjectMethod{
+			description: "Returns the first match of the regex as hash or `null` if the string does not match. All capture groups are available by their number, named groups also by their name.",
+			example: `🚀 > "2021-12-24".match(regex("(?P<year>[0-9]+)-([0-9]+)"))
+=> {0: "2021-12", 1: "2021", 2: "12", "year": "2021"}
+🚀 > "test".match(regex("[0-9]"))
+=> null`,
+			argPattern: [][]string{
+				[]string{REGEX_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{HASH_OBJ, NULL_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				return regexMatch(args[0].(*Regex), o.(*String).Value)
+			},
+		},
+		"match_all": ObjectMethod{
+			description: "Returns all matches of the regex as array of hashes like `match()` does.",
+			example: `🚀 > "a1 b2".match_all(regex("([a-z])([0-9])"))
+=> [{0: "a1", 1: "a", 2: "1"}, {0: "b2", 1: "b", 2: "2"}]`,
+			argPattern: [][]string{
+				[]string{REGEX_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ARRAY_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				s := o.(*String)
+				re := args[0].(*Regex).Regexp
+
+				matches := re.FindAllStringSubmatchIndex(s.Value, -1)
+				result := make([]Object, len(matches))
+				for i, loc := range matches {
+					result[i] = newMatchHash(re, s.Value, loc)
+				}
+				return NewArray(result)
+			},
+		},
+		"scan": ObjectMethod{
+			description: "Returns all matches of the regex as array of strings. If the regex contains capture groups every element is an array of the captured strings instead.",
+			example: `🚀 > "a1 b22".scan(regex("[0-9]+"))
+=> ["1", "22"]
+🚀 > "a1 b22".scan(regex("([a-z])([0-9]+)"))
+=> [["a", "1"], ["b", "22"]]`,
+			argPattern: [][]string{
+				[]string{REGEX_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{ARRAY_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				s := o.(*String)
+				re := args[0].(*Regex).Regexp
+
+				matches := re.FindAllStringSubmatch(s.Value, -1)
+				result := make([]Object, len(matches))
+				for i, match := range matches {
+					if len(match) == 1 {
+						result[i] = NewString(match[0])
+					} else {
+						result[i] = NewStringArray(match[1:])
+					}
+				}
+				return NewArray(result)
+			},
+		},
+		"gsub": ObjectMethod{
+			description: "Replaces all matches of the regex. The replacement is either a string, which can refer to capture groups with `$1` or `${name}`, or a function that gets called with every match and returns the replacement.",
+			example: `🚀 > "a1 b22".gsub(regex("[0-9]+"), "#")
+=> "a# b#"
+🚀 > "a1 b22".gsub(regex("([a-z])([0-9]+)"), "$2$1")
+=> "1a 22b"
+🚀 > "a1 b22".gsub(regex("[a-z]"), def(m) { m.upcase() })
+=> "A1 B22"`,
+			argPattern: [][]string{
+				[]string{REGEX_OBJ},
+				[]string{STRING_OBJ, FUNCTION_OBJ, BUILTIN_OBJ},
+			},
+			returnPattern: [][]string{
+				[]string{STRING_OBJ, ERROR_OBJ},
+			},
+			method: func(o Object, args []Object) Object {
+				s := o.(*String)
+				re := args[0].(*Regex).Regexp
+
+				if replacement, ok := args[1].(*String); ok {
+					return NewString(re.ReplaceAllString(s.Value, replacement.Value))
+				}
+
+				var err Object
+				result := re.ReplaceAllStringFunc(s.Value, func(match string) string {
+					if err != nil {
+						return match
+					}
+					replacement := ApplyFunction(args[1], []Object{NewString(match)})
+					if str, ok := replacement.(*String); ok {
+						return str.Value
+					}
+					if IsError(replacement) {
+						err = replacement
+					} else {
+						err = NewErrorFormat("replacement function must return STRING, got %s", replacement.Type())
+					}
+					return match
+				})
+				if err != nil {
+					return err
+				}
+				return NewString(result)
+			},
+		},
 		"lines": ObjectMethod{
 			description: "Splits the string at newline escape sequence and return all chunks in an array. Shorthand for `string.split(\"\\n\")`.",
 			example: `🚀 > "test\ntest2".lines()
@@ -317,30 +456,25 @@ func init() {
 
 func (s *String) Type() ObjectType { return STRING_OBJ }
 func (s *String) Inspect() string  { return `"` + s.Value + `"` }
+func (s *String) Display() string  { return s.Value }
 func (s *String) InvokeMethod(method string, env Environment, args ...Object) Object {
 	return objectMethodLookup(s, method, args)
 }
 
 func (s *String) HashKey() HashKey {
-	h := fnv.New64a()
-	h.Write([]byte(s.Value))
-
-	return HashKey{Type: s.Type(), Value: h.Sum64()}
-}
-
-func (s *String) Reset() {
-	s.offset = 0
+	return HashKey{Type: s.Type(), Value: HashBytes([]byte(s.Value))}
 }
 
-func (s *String) Next() (Object, Object, bool) {
-	if s.offset < utf8.RuneCountInString(s.Value) {
-		s.offset++
-
-		chars := []rune(s.Value)
-		val := NewString(string(chars[s.offset-1]))
-
-		return val, NewInteger(int64(s.offset - 1)), true
-	}
+// Iter returns an iterator over the characters and their indices.
+func (s *String) Iter() *Iterator {
+	chars := []rune(s.Value)
 
-	return nil, NewInteger(0), false
+	var offset int
+	return NewIterator(func() (Object, Object, bool) {
+		if offset >= len(chars) {
+			return nil, nil, false
+		}
+		offset++
+		return NewString(string(chars[offset-1])), NewInteger(int64(offset - 1)), true
+	})
 }
@@ -50,7 +50,7 @@ func TestStringObjectMethods(t *testing.T) {
 		{`"test test1".split()`, `["test", "test1"]`},
 		{`"test test1".split(",")`, `["test test1"]`},
 		{`"test test1".split(",", "x")`, `to many arguments: want=1, got=2`},
-		{`"test".split(1)`, `wrong argument type on position 0: got=INTEGER, want=STRING`},
+		{`"test".split(1)`, `wrong argument type on position 0: got=INTEGER, want=STRING|REGEX`},
 		{`"test ".strip()`, "test"},
 		{`" test ".strip()`, "test"},
 		{`"test".strip()`, "test"},
@@ -86,6 +86,23 @@ func TestStringObjectMethods(t *testing.T) {
 		{`a = "test"; b = []; foreach char in a { b.yoink(char) }; b.size()`, 4},
 		{`"test" * 2`, "testtest"},
 		{`2 * "test"`, "testtest"},
+		{`"a1b22c".split(regex("[0-9]+"))`, `["a", "b", "c"]`},
+		{`"2021-12".match(regex("(?P<year>[0-9]+)-([0-9]+)"))["year"]`, "2021"},
+		{`"2021-12".match(regex("(?P<year>[0-9]+)-([0-9]+)"))[2]`, "12"},
+		{`"2021-12".match(regex("(?P<year>[0-9]+)-([0-9]+)"))[0]`, "2021-12"},
+		{`"a".match(regex("(a)|(b)"))[2]`, "NULL"},
+		{`"test".match(regex("[0-9]"))`, "NULL"},
+		{`"test".match("t")`, "wrong argument type on position 0: got=STRING, want=REGEX"},
+		{`"a1 b2".match_all(regex("([a-z])([0-9])")).size()`, 2},
+		{`"a1 b2".match_all(regex("([a-z])([0-9])"))[1][1]`, "b"},
+		{`"a1 b22".scan(regex("[0-9]+"))`, `["1", "22"]`},
+		{`"a1 b22".scan(regex("([a-z])([0-9]+)"))`, `[["a", "1"], ["b", "22"]]`},
+		{`"a1 b22".gsub(regex("[0-9]+"), "#")`, "a# b#"},
+		{`"a1 b22".gsub(regex("([a-z])([0-9]+)"), "$2$1")`, "1a 22b"},
+		{`"a1 b22".gsub(regex("(?P<l>[a-z])"), "${l}${l}")`, "aa1 bb22"},
+		{`"a1 b22".gsub(regex("[a-z]"), def(m) { m.upcase() })`, "A1 B22"},
+		{`"a1".gsub(regex("[a-z]"), def(m) { 1 })`, "replacement function must return STRING, got INTEGER"},
+		{`"a1".gsub(regex("[a-z]"), def(m) { m - 1 })`, "type mismatch: STRING - INTEGER"},
 	}
 
 	testInput(t, tests)
@@ -0,0 +1,81 @@
+package object
+
+import (
+	"strconv"
+	"unicode"
+)
+
+// Symbol is a name like :id, mostly used as hash key. Symbols are
+// interned, there is only one symbol of every name, so they are compared
+// and hashed without looking at the name.
+type Symbol struct {
+	Name string
+	id   uint64
+}
+
+// symbols holds every symbol created so far by name.
+var symbols = map[string]*Symbol{}
+
+// NewSymbol returns the symbol of name, which is created on first use.
+func NewSymbol(name string) *Symbol {
+	if s, ok := symbols[name]; ok {
+		return s
+	}
+
+	s := &Symbol{Name: name, id: uint64(len(symbols))}
+	symbols[name] = s
+	return s
+}
+
+// LookupSymbol returns the symbol of name if it was created already.
+func LookupSymbol(name string) (*Symbol, bool) {
+	s, ok := symbols[name]
+	return s, ok
+}
+
+func (s *Symbol) Type() ObjectType { return SYMBOL_OBJ }
+func (s *Symbol) HashKey() HashKey { return HashKey{Type: s.Type(), Value: s.id} }
+
+// Inspect returns the symbol as written in code, names which are no
+// identifier are quoted.
+func (s *Symbol) Inspect() string {
+	if isSymbolName(s.Name) {
+		return ":" + s.Name
+	}
+	return ":" + strconv.Quote(s.Name)
+}
+func (s *Symbol) Display() string { return s.Name }
+func (s *Symbol) InvokeMethod(method string, env Environment, args ...Object) Object {
+	return objectMethodLookup(s, method, args)
+}
+
+// isSymbolName reports whether name can be written as :name.
+func isSymbolName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		last := i == len(name)-1
+		if !unicode.IsLetter(r) && r != '_' && !(last && (r == '?' || r == '!')) {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	objectMethods[SYMBOL_OBJ] = map[string]ObjectMethod{
+		"plz_s": ObjectMethod{
+			description: "Returns the name of the symbol.",
+			example: `🚀 > :name.plz_s()
+=> "name"`,
+			returnPattern: [][]string{
+				[]string{STRING_OBJ},
+			},
+			method: func(o Object, _ []Object) Object {
+				return NewString(o.(*Symbol).Name)
+			},
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+func TestSymbolObjectMethods(t *testing.T) {
+	tests := []inputTestCase{
+		{":name.plz_s()", "name"},
+		{":name.to_s()", "name"},
+		{":name.inspect()", ":name"},
+		{":name.type()", "SYMBOL"},
+		{`"name".to_sym() == :name`, true},
+		{`"first name".to_sym().inspect()`, `:"first name"`},
+		{":a == :b", false},
+		{`:a == "a"`, false},
+		{`{name: 1, "name": 2}.keys().size()`, 2},
+		{`{name: 1}[(:name)]`, 1},
+		{`{name: 1}.name`, 1},
+		{`{name: 1, "name": 2}.name`, 2},
+		{`h = {name: 1}; h.name += 1; [h.keys(), h[(:name)]]`, "[[:name], 2]"},
+		{`h = {}; h.name = 1; h.keys()`, `["name"]`},
+		{`{name: 1, :done?: true, "a": :b}.inspect()`, `{"a": :b, done?: true, name: 1}`},
+		{`{"first name".to_sym(): 1}.inspect()`, `{:"first name": 1}`},
+		{":name.nope()", "undefined method `.nope()` for SYMBOL"},
+	}
+
+	testInput(t, tests)
+}
+
+func TestSymbolsAreInterned(t *testing.T) {
+	a := object.NewSymbol("interned")
+	b := object.NewSymbol("interned")
+	c := object.NewSymbol("other")
+
+	if a != b {
+		t.Errorf("symbols with the same name are different objects")
+	}
+	if a.HashKey() != b.HashKey() {
+		t.Errorf("symbols with the same name have different hash keys")
+	}
+	if a.HashKey() == c.HashKey() {
+		t.Errorf("symbols with different names have the same hash key")
+	}
+	if !object.CompareObjects(a, b) || object.CompareObjects(a, c) {
+		t.Errorf("symbols are compared by identity")
+	}
+	if s, ok := object.LookupSymbol("interned"); !ok || s != a {
+		t.Errorf("LookupSymbol did not find the interned symbol")
+	}
+	if _, ok := object.LookupSymbol("never created"); ok {
+		t.Errorf("LookupSymbol found a symbol which was never created")
+	}
+}
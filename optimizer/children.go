@@ -0,0 +1,147 @@
+package optimizer
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+)
+
+// children replaces the children of node which are evaluated as
+// expressions or blocks by the result of fn. Parameters, loop variables,
+// assigned variables and method names are left out.
+func children(node ast.Node, fn func(ast.Node) ast.Node) {
+	expression := func(e ast.Expression) ast.Expression {
+		if e == nil {
+			return nil
+		}
+		return fn(e)
+	}
+	block := func(b *ast.Block) *ast.Block {
+		if b != nil {
+			fn(b)
+		}
+		return b
+	}
+
+	switch node := node.(type) {
+	case *ast.Program:
+		for i, statement := range node.Statements {
+			node.Statements[i] = fn(statement)
+		}
+	case *ast.Block:
+		if node == nil {
+			return
+		}
+		for i, statement := range node.Statements {
+			node.Statements[i] = fn(statement)
+		}
+	case *ast.ExpressionStatement:
+		node.Expression = expression(node.Expression)
+	case *ast.Return:
+		node.ReturnValue = expression(node.ReturnValue)
+	case *ast.Yield:
+		node.Value = expression(node.Value)
+	case *ast.Assign:
+		target(node.Name, expression)
+		node.Value = expression(node.Value)
+	case *ast.Import:
+		node.Name = expression(node.Name)
+	case *ast.Function:
+		block(node.Body)
+	case *ast.Foreach:
+		node.Value = expression(node.Value)
+		if node.Pattern != nil {
+			target(node.Pattern, expression)
+		}
+		block(node.Body)
+	case *ast.While:
+		node.Condition = expression(node.Condition)
+		block(node.Body)
+	case *ast.Until:
+		node.Condition = expression(node.Condition)
+		block(node.Body)
+	case *ast.Loop:
+		block(node.Body)
+	case *ast.Modifier:
+		node.Statement = expression(node.Statement)
+		node.Condition = expression(node.Condition)
+	case *ast.Comprehension:
+		for i := range node.Clauses {
+			clause := &node.Clauses[i]
+			clause.Foreach.Value = expression(clause.Foreach.Value)
+			if clause.Foreach.Pattern != nil {
+				target(clause.Foreach.Pattern, expression)
+			}
+			clause.Condition = expression(clause.Condition)
+		}
+		node.Key = expression(node.Key)
+		node.Value = expression(node.Value)
+	case *ast.Array:
+		for i, element := range node.Elements {
+			node.Elements[i] = expression(element)
+		}
+	case *ast.Hash:
+		for i := range node.Pairs {
+			node.Pairs[i].Key = expression(node.Pairs[i].Key)
+			node.Pairs[i].Value = expression(node.Pairs[i].Value)
+		}
+	case *ast.Splat:
+		node.Value = expression(node.Value)
+	case *ast.Prefix:
+		node.Right = expression(node.Right)
+	case *ast.Infix:
+		node.Left = expression(node.Left)
+		node.Right = expression(node.Right)
+	case *ast.If:
+		node.Condition = expression(node.Condition)
+		block(node.Consequence)
+		block(node.Alternative)
+	case *ast.Unless:
+		node.Condition = expression(node.Condition)
+		block(node.Consequence)
+		block(node.Alternative)
+	case *ast.Ternary:
+		node.Condition = expression(node.Condition)
+		node.Consequence = expression(node.Consequence)
+		node.Alternative = expression(node.Alternative)
+	case *ast.Call:
+		node.Callable = expression(node.Callable)
+		for i, argument := range node.Arguments {
+			node.Arguments[i] = expression(argument)
+		}
+	case *ast.ObjectCall:
+		node.Object = expression(node.Object)
+		if call, ok := node.Call.(*ast.Call); ok {
+			for i, argument := range call.Arguments {
+				call.Arguments[i] = expression(argument)
+			}
+		}
+	case *ast.Index:
+		node.Left = expression(node.Left)
+		node.Index = expression(node.Index)
+	case *ast.RangeIndex:
+		node.Left = expression(node.Left)
+		node.FirstIndex = expression(node.FirstIndex)
+		node.SecondIndex = expression(node.SecondIndex)
+	}
+}
+
+// target passes the parts of an assignment target which are evaluated to
+// fn: indexes, objects of properties and keys of hash patterns.
+func target(t ast.Expression, fn func(ast.Expression) ast.Expression) {
+	switch t := t.(type) {
+	case *ast.Index:
+		t.Index = fn(t.Index)
+	case *ast.ObjectCall:
+		t.Object = fn(t.Object)
+	case *ast.Splat:
+		target(t.Value, fn)
+	case *ast.Array:
+		for _, element := range t.Elements {
+			target(element, fn)
+		}
+	case *ast.Hash:
+		for i := range t.Pairs {
+			t.Pairs[i].Key = fn(t.Pairs[i].Key)
+			target(t.Pairs[i].Value, fn)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Package optimizer simplifies a parsed program before it is evaluated. It
+// folds operations on literals, removes branches which can't be taken and
+// code after a return, and replaces variables which are assigned a literal
+// only once by that literal.
+package optimizer
+
+import (
+	"math"
+	"path/filepath"
+	"strconv"
+
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/evaluator"
+	"github.com/flipez/rocket-lang/object"
+	"github.com/flipez/rocket-lang/token"
+)
+
+// MaxFoldedString is the length of the longest string created by folding.
+// Longer ones are left to the runtime, which only builds them if the code
+// runs. The length is computed from the operands, so the optimizer never
+// builds them either.
+const MaxFoldedString = 1024
+
+// MaxFoldedExponent is the largest integer exponent folded, larger ones
+// overflow for every base but -1, 0 and 1.
+const MaxFoldedExponent = 64
+
+// Optimize returns program with its statements simplified. The program
+// must be resolved by the parser, the optimized program keeps working with
+// the same environments.
+func Optimize(program *ast.Program) *ast.Program {
+	o := &optimizer{writes: map[string]int{}, constants: map[string]ast.Expression{}}
+	o.countWrites(program)
+	program.Statements = o.statements(program.Statements)
+	return program
+}
+
+type optimizer struct {
+	// writes counts the places assigning each name, including parameters
+	// and loop variables
+	writes map[string]int
+
+	// anyWrites is set if a place assigns to a name only known at runtime
+	anyWrites bool
+
+	// constants are the variables which are only assigned a literal, for
+	// the rest of the block assigning them
+	constants map[string]ast.Expression
+}
+
+// countWrites counts the places assigning to variables in node.
+func (o *optimizer) countWrites(node ast.Node) {
+	switch node := node.(type) {
+	case *ast.Assign:
+		o.countTarget(node.Name)
+	case *ast.Function:
+		if node.Name != "" {
+			o.writes[node.Name]++
+		}
+		for _, param := range node.Parameters {
+			o.writes[param.Value]++
+		}
+	case *ast.Foreach:
+		o.countForeach(node)
+	case *ast.Comprehension:
+		for _, clause := range node.Clauses {
+			o.countForeach(clause.Foreach)
+		}
+	case *ast.Import:
+		if name, ok := node.Name.(*ast.String); ok {
+			o.writes[filepath.Base(name.Value)]++
+		} else {
+			o.anyWrites = true
+		}
+	}
+
+	children(node, func(child ast.Node) ast.Node {
+		o.countWrites(child)
+		return child
+	})
+}
+
+func (o *optimizer) countForeach(fe *ast.Foreach) {
+	if fe.Index != "" {
+		o.writes[fe.Index]++
+	}
+	if fe.Pattern != nil {
+		o.countTarget(fe.Pattern)
+	} else {
+		o.writes[fe.Ident]++
+	}
+}
+
+func (o *optimizer) countTarget(target ast.Expression) {
+	switch t := target.(type) {
+	case *ast.Identifier:
+		o.writes[t.Value]++
+	case *ast.Splat:
+		o.countTarget(t.Value)
+	case *ast.Array:
+		for _, element := range t.Elements {
+			o.countTarget(element)
+		}
+	case *ast.Hash:
+		for _, pair := range t.Pairs {
+			o.countTarget(pair.Value)
+		}
+	}
+}
+
+// statements optimizes the statements of a block. Statements after a
+// return are dropped. A variable assigned a literal by one of them is
+// replaced in the following ones if nothing else assigns to it.
+func (o *optimizer) statements(statements []ast.Statement) []ast.Statement {
+	var defined []string
+	result := make([]ast.Statement, 0, len(statements))
+
+	for _, statement := range statements {
+		statement = o.optimize(statement)
+		result = append(result, statement)
+
+		if _, ok := statement.(*ast.Return); ok {
+			break
+		}
+		if name, value, ok := o.constantAssignment(statement); ok {
+			o.constants[name] = value
+			defined = append(defined, name)
+		}
+	}
+
+	for _, name := range defined {
+		delete(o.constants, name)
+	}
+	return result
+}
+
+// constantAssignment returns the name and value of statement if it assigns
+// a literal to a variable which is assigned nowhere else. Strings are
+// left out, they can be changed in place.
+func (o *optimizer) constantAssignment(statement ast.Statement) (string, ast.Expression, bool) {
+	if expression, ok := statement.(*ast.ExpressionStatement); ok {
+		statement = expression.Expression
+	}
+
+	assign, ok := statement.(*ast.Assign)
+	if !ok || (assign.Operator != "" && assign.Operator != "=") {
+		return "", nil, false
+	}
+
+	name, ok := assign.Name.(*ast.Identifier)
+	if !ok || o.writes[name.Value] != 1 || o.anyWrites {
+		return "", nil, false
+	}
+
+	switch assign.Value.(type) {
+	case *ast.Integer, *ast.Float, *ast.Boolean, *ast.Null, *ast.Symbol:
+		return name.Value, assign.Value, true
+	}
+	return "", nil, false
+}
+
+// optimize returns the optimized node, after optimizing its children.
+func (o *optimizer) optimize(node ast.Node) ast.Node {
+	switch node := node.(type) {
+	case *ast.Program:
+		node.Statements = o.statements(node.Statements)
+		return node
+	case *ast.Block:
+		node.Statements = o.statements(node.Statements)
+		return node
+	case *ast.Identifier:
+		// identifiers which are not declared may be builtins
+		if value, ok := o.constants[node.Value]; ok && node.Resolved && node.Slot != -1 {
+			return value
+		}
+		return node
+	}
+
+	children(node, o.optimize)
+	return fold(node)
+}
+
+// fold returns the literal node evaluates to if its operands are literals
+// already, or node itself.
+func fold(node ast.Node) ast.Node {
+	switch node := node.(type) {
+	case *ast.Prefix:
+		if isLiteral(node.Right) {
+			return evaluate(node, node.Token)
+		}
+	case *ast.Infix:
+		return foldInfix(node)
+	case *ast.Ternary:
+		if truthy, ok := literalTruthiness(node.Condition); ok {
+			return branch(truthy, node.Consequence, node.Alternative, node.Token)
+		}
+	case *ast.If:
+		if truthy, ok := literalTruthiness(node.Condition); ok {
+			return branch(truthy, node.Consequence, node.Alternative, node.Token)
+		}
+	case *ast.Unless:
+		if truthy, ok := literalTruthiness(node.Condition); ok {
+			return branch(!truthy, node.Consequence, node.Alternative, node.Token)
+		}
+	case *ast.Modifier:
+		truthy, ok := literalTruthiness(node.Condition)
+		if !ok {
+			return node
+		}
+		switch node.Token.Type {
+		case token.IF:
+			return branch(truthy, node.Statement, nil, node.Token)
+		case token.UNLESS:
+			return branch(!truthy, node.Statement, nil, node.Token)
+		case token.WHILE:
+			if !truthy {
+				return null(node.Token)
+			}
+		case token.UNTIL:
+			if truthy {
+				return null(node.Token)
+			}
+		}
+	case *ast.While:
+		if truthy, ok := literalTruthiness(node.Condition); ok && !truthy {
+			return null(node.Token)
+		}
+	case *ast.Until:
+		if truthy, ok := literalTruthiness(node.Condition); ok && truthy {
+			return null(node.Token)
+		}
+	}
+	return node
+}
+
+func foldInfix(node *ast.Infix) ast.Node {
+	left, right := isLiteral(node.Left), isLiteral(node.Right)
+
+	switch node.Operator {
+	case "&&", "||":
+		// the right side is not evaluated if the left one decides
+		if truthy, ok := literalTruthiness(node.Left); ok && truthy == (node.Operator == "||") {
+			return boolean(truthy, node.Token)
+		}
+	case "??":
+		if _, ok := node.Left.(*ast.Null); ok {
+			return node.Right
+		}
+		if left {
+			return node.Left
+		}
+	case "**":
+		if exponent, ok := node.Right.(*ast.Integer); ok && exponent.Value > MaxFoldedExponent {
+			return node
+		}
+	case "+", "*":
+		if length, ok := stringLength(node); ok && (length < 0 || length > MaxFoldedString) {
+			return node
+		}
+	case "|>":
+		return node
+	}
+
+	if left && right {
+		return evaluate(node, node.Token)
+	}
+	return node
+}
+
+// branch returns the node taken by a conditional, or null if there is no
+// such node.
+func branch(truthy bool, consequence, alternative ast.Node, tok token.Token) ast.Node {
+	taken := consequence
+	if !truthy {
+		taken = alternative
+	}
+
+	switch taken := taken.(type) {
+	case nil:
+		return null(tok)
+	case *ast.Block:
+		if taken == nil {
+			return null(tok)
+		}
+	}
+	return taken
+}
+
+// stringLength returns the length of the string built by adding or
+// repeating the literal operands of node, without building it. It is negative
+// for a negative repetition count and saturates instead of overflowing.
+func stringLength(node *ast.Infix) (int64, bool) {
+	switch left := node.Left.(type) {
+	case *ast.String:
+		switch right := node.Right.(type) {
+		case *ast.String:
+			return int64(len(left.Value)) + int64(len(right.Value)), node.Operator == "+"
+		case *ast.Integer:
+			return repeatedLength(left.Value, right.Value), node.Operator == "*"
+		}
+	case *ast.Integer:
+		if right, ok := node.Right.(*ast.String); ok {
+			return repeatedLength(right.Value, left.Value), node.Operator == "*"
+		}
+	}
+	return 0, false
+}
+
+func repeatedLength(s string, count int64) int64 {
+	if count < 0 {
+		return -1
+	}
+	if count > 0 && int64(len(s)) > math.MaxInt64/count {
+		return math.MaxInt64
+	}
+	return int64(len(s)) * count
+}
+
+// evaluate returns the literal of the value of node, which only contains
+// literals. Errors are left to the runtime.
+func evaluate(node ast.Node, tok token.Token) ast.Node {
+	switch value := evaluator.Eval(node, object.NewEnvironment()).(type) {
+	case *object.Integer:
+		tok.Type, tok.Literal = token.INT, strconv.FormatInt(value.Value, 10)
+		return &ast.Integer{Token: tok, Value: value.Value}
+	case *object.Float:
+		tok.Type, tok.Literal = token.FLOAT, value.Inspect()
+		return &ast.Float{Token: tok, Value: value.Value}
+	case *object.String:
+		tok.Type, tok.Literal = token.STRING, ast.Quote(value.Value)
+		return &ast.String{Token: tok, Value: value.Value, Delimiter: `"`}
+	case *object.Symbol:
+		tok.Type, tok.Literal = token.SYMBOL, value.Inspect()
+		return &ast.Symbol{Token: tok, Value: value.Name}
+	case *object.Boolean:
+		return boolean(value.Value, tok)
+	case *object.Null:
+		return null(tok)
+	}
+	return node
+}
+
+func boolean(value bool, tok token.Token) *ast.Boolean {
+	tok.Type, tok.Literal = token.FALSE, "false"
+	if value {
+		tok.Type, tok.Literal = token.TRUE, "true"
+	}
+	return &ast.Boolean{Token: tok, Value: value}
+}
+
+func null(tok token.Token) *ast.Null {
+	tok.Type, tok.Literal = token.NULL, "null"
+	return &ast.Null{Token: tok}
+}
+
+func isLiteral(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.Integer, *ast.Float, *ast.String, *ast.Symbol, *ast.Boolean, *ast.Null:
+		return true
+	}
+	return false
+}
+
+// literalTruthiness returns the truthiness of node if it is a literal.
+func literalTruthiness(node ast.Node) (bool, bool) {
+	if !isLiteral(node) {
+		return false, false
+	}
+	return object.IsTruthy(evaluator.Eval(node, object.NewEnvironment())), true
+}
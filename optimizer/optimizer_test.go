@@ -0,0 +1,106 @@
+package optimizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/evaluator"
+	"github.com/flipez/rocket-lang/lexer"
+	"github.com/flipez/rocket-lang/object"
+	"github.com/flipez/rocket-lang/parser"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input), nil)
+	program, _ := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("%q: parser errors: %v", input, p.Errors())
+	}
+	return program
+}
+
+func TestOptimize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"60 * 60 * 24", "86400"},
+		{"-(2 + 3)", "-5"},
+		{"1 + 2.5", "3.5"},
+		{`"a" + "b" * 2`, `"abb"`},
+		{"1 < 2 == true", "true"},
+		{":a == :a", "true"},
+		{`"id".to_sym() == :id ? :yes : :no`, `("id".to_sym() == :id) ? :yes : :no`},
+		{"k = :id; {k: 1, (k): 2}", "k = :id{k:1, :id:2}"},
+		{"!true || 2 > 3", "false"},
+		{"x = 1; false && x", "x = 1false"},
+		{"x = [1]; true || x.size()", "x = [1]true"},
+		{"x = [1]; true && x", "x = [1](true && x)"},
+		{"x = [1]; null ?? x", "x = [1]x"},
+		{"x = [1]; 1 ?? x", "x = [1]1"},
+		{"1 / 0", "(1 / 0)"},
+		{"1 % 0", "(1 % 0)"},
+		{`"a" - "b"`, `("a" - "b")`},
+		{"2 ** 65", "(2 ** 65)"},
+		{`2 * "ab"`, `"abab"`},
+		{`"abcd" * 500000000`, `("abcd" * 500000000)`},
+		{`"ab" * 9223372036854775807`, `("ab" * 9223372036854775807)`},
+		{`"ab" * -1`, `("ab" * -1)`},
+		{`"` + strings.Repeat("a", 1000) + `" + "` + strings.Repeat("b", 24) + `"`, `"` + strings.Repeat("a", 1000) + strings.Repeat("b", 24) + `"`},
+		{`"` + strings.Repeat("a", 1000) + `" + "` + strings.Repeat("b", 25) + `"`, `("` + strings.Repeat("a", 1000) + `" + "` + strings.Repeat("b", 25) + `")`},
+		{"true ? 1 : 2", "1"},
+		{"false ? 1 : 2 + 3", "5"},
+		{"false ? 1", "null"},
+		{"if (1 > 2) { 1 } else { 2 }", "2"},
+		{"if (false) { 1 }", "null"},
+		{"if (false) { 1 } elsif (true) { 2 } else { 3 }", "2"},
+		{"unless (false) { 1 }", "1"},
+		{"puts(1) if false", "null"},
+		{"puts(1) unless false", "puts(1)"},
+		{"puts(1) while false", "null"},
+		{"while (false) { puts(1) }", "null"},
+		{"until (true) { puts(1) }", "null"},
+		{"def() { return 1; puts(2) }", "def() return (1)"},
+		{"def() { if (true) { return 1 }; 2 }", "def() return (1)2"},
+		{"a = 2; b = a * 3; b + 1", "a = 2b = 67"},
+		{"a = 2; a = 3; a", "a = 2a = 3a"},
+		{"a = 2; a += 1; a", "a = 2a += 1a"},
+		{`s = "x"; s`, `s = "x"s`},
+		{"a; a = 1", "aa = 1"},
+		{"if (true) { a = 1 }; a", "a = 1a"},
+		{"def f(a) { a }; a = 1; a", "def(a) aa = 1a"},
+		{"a = 1; def() { a + 1 }", "a = 1def() 2"},
+		{"a = 1; foreach a in [2] { a }; a", "a = 1foreach a in [2] {\n  a\n}a"},
+		{"h = {}; h[1 + 1] = 2 * 2", "h = {}(h[2]) = 4"},
+		{"puts(1 + 1).plz_s()", "puts(2).plz_s()"},
+	}
+
+	for _, tt := range tests {
+		program := Optimize(parse(t, tt.input))
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestOptimizedProgramsBehaveTheSame(t *testing.T) {
+	tests := []string{
+		"x = 5; y = x * 2; def f(n) { n + y }; f(x)",
+		"a = 1; f = def() { a }; a = 2; f()",
+		"n = 0; while (n < 10) { n += 1 }; n",
+		"def f(n) { if (n < 2) { return n }; return f(n - 1) + f(n - 2) }; f(10)",
+		"1 / 0",
+		"x = 1; [x * y foreach y in [1, 2, 3] if y > x]",
+		"s = \"abc\"; s[0] = \"x\"; s",
+		"d = false; d ? 1 : 2",
+	}
+
+	for _, input := range tests {
+		expected := evaluator.Eval(parse(t, input), object.NewEnvironment())
+		actual := evaluator.Eval(Optimize(parse(t, input)), object.NewEnvironment())
+		if actual.Inspect() != expected.Inspect() {
+			t.Errorf("%q: expected=%s, got=%s", input, expected.Inspect(), actual.Inspect())
+		}
+	}
+}
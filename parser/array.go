@@ -6,9 +6,33 @@ import (
 )
 
 func (p *Parser) parseArray() ast.Expression {
+	defer p.nest()()
 	array := &ast.Array{Token: p.curToken}
+	array.Elements = []ast.Expression{}
 
-	array.Elements = p.parseExpressionList(token.RBRACKET)
+	if p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		return array
+	}
+
+	p.nextToken()
+	first := p.parseExpression(LOWEST)
+
+	// `[x * 2 foreach x in xs]`
+	if p.peekTokenIs(token.FOREACH) {
+		return p.parseComprehension(array.Token, nil, first, token.RBRACKET)
+	}
+	array.Elements = append(array.Elements, first)
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		array.Elements = append(array.Elements, p.parseExpression(LOWEST))
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
 
 	return array
 }
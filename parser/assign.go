@@ -7,11 +7,20 @@ import (
 )
 
 func (p *Parser) parseAssignExpression(name ast.Expression) ast.Expression {
-	stmt := &ast.Assign{Token: p.curToken}
+	stmt := &ast.Assign{Token: p.curToken, Operator: p.curToken.Literal}
 	if n, ok := name.(*ast.Identifier); ok {
 		stmt.Name = n
 	} else if index, ok := name.(*ast.Index); ok {
 		stmt.Name = index
+	} else if property, ok := name.(*ast.ObjectCall); ok && isProperty(property) {
+		stmt.Name = property
+	} else if isDestructuring(name) {
+		if stmt.Operator != "=" {
+			msg := fmt.Sprintf("%d:%d: cannot use %s to destructure", p.curToken.LineNumber, p.curToken.LinePosition, stmt.Operator)
+			p.errors = append(p.errors, msg)
+		}
+		p.checkPattern(name)
+		stmt.Name = name
 	} else {
 		msg := fmt.Sprintf("%d:%d: expected assign token to be IDENT, got %s instead", p.curToken.LineNumber, p.curToken.LinePosition, name.TokenLiteral())
 		p.errors = append(p.errors, msg)
@@ -21,3 +30,18 @@ func (p *Parser) parseAssignExpression(name ast.Expression) ast.Expression {
 	stmt.Value = p.parseExpression(LOWEST)
 	return stmt
 }
+
+// isProperty reports whether call is a property access without parentheses
+// like `h.key`, which can be assigned to.
+func isProperty(call *ast.ObjectCall) bool {
+	_, ok := call.Call.(*ast.Identifier)
+	return ok && !call.Safe
+}
+
+func isDestructuring(target ast.Expression) bool {
+	switch target.(type) {
+	case *ast.Array, *ast.Hash:
+		return true
+	}
+	return false
+}
@@ -7,6 +7,17 @@ import (
 
 func (p *Parser) parseCall(callable ast.Expression) ast.Expression {
 	exp := &ast.Call{Token: p.curToken, Callable: callable}
+
+	outerNoBlocks := p.noBlocks
+	p.noBlocks = false
 	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	p.noBlocks = outerNoBlocks
+
+	// a trailing block is passed as the last argument
+	if exp.Arguments != nil && p.peekStartsBlock() {
+		p.nextToken()
+		exp.Arguments = append(exp.Arguments, p.parseBlockArgument())
+	}
+
 	return exp
 }
@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/token"
+)
+
+// parseComprehension parses the foreach clauses of a comprehension after
+// its first value, each of them may be followed by an if condition. The
+// current token is the end of the value, end closes the comprehension.
+func (p *Parser) parseComprehension(tok token.Token, key, value ast.Expression, end token.TokenType) ast.Expression {
+	comprehension := &ast.Comprehension{Token: tok, Key: key, Value: value}
+
+	for p.peekTokenIs(token.FOREACH) {
+		p.nextToken()
+		clause := ast.ComprehensionClause{Foreach: p.parseForEachHeader()}
+		if clause.Foreach == nil {
+			return nil
+		}
+
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			p.nextToken()
+			clause.Condition = p.parseExpression(LOWEST)
+		}
+
+		comprehension.Clauses = append(comprehension.Clauses, clause)
+	}
+
+	if !p.expectPeek(end) {
+		return nil
+	}
+
+	return comprehension
+}
@@ -19,6 +19,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 	for !p.peekTokenIs(token.SEMICOLON) &&
 		!p.peekTokenIs(token.COLON) &&
+		!p.peekStartsPattern() &&
 		precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
 		if infix == nil {
@@ -33,7 +34,27 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	return leftExp
 }
 
+// peekStartsPattern reports whether the next token is a `[` or `*` at the
+// start of a new line after a statement. These begin a destructuring pattern
+// like `[a, b] = pair` or `*init, last = list` instead of continuing the
+// statement as index or multiplication. Inside brackets they always continue
+// the expression.
+func (p *Parser) peekStartsPattern() bool {
+	return p.statementLevel &&
+		(p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.ASTERISK)) &&
+		p.peekToken.LineNumber > p.curToken.EndLineNumber
+}
+
+// nest marks the parser as inside brackets until the returned function is
+// called, e.g. `defer p.nest()()`.
+func (p *Parser) nest() func() {
+	outer := p.statementLevel
+	p.statementLevel = false
+	return func() { p.statementLevel = outer }
+}
+
 func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	defer p.nest()()
 	list := []ast.Expression{}
 
 	if p.peekTokenIs(end) {
@@ -58,9 +79,13 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 }
 
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	defer p.nest()()
 	p.nextToken()
 
+	outerNoBlocks := p.noBlocks
+	p.noBlocks = false
 	exp := p.parseExpression(LOWEST)
+	p.noBlocks = outerNoBlocks
 
 	if !p.expectPeek(token.RPAREN) {
 		return nil
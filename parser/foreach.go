@@ -8,28 +8,53 @@ import (
 )
 
 func (p *Parser) parseForEach() ast.Expression {
-	expression := &ast.Foreach{Token: p.curToken}
+	expression := p.parseForEachHeader()
+	if expression == nil {
+		return nil
+	}
 
 	p.nextToken()
-	expression.Ident = p.curToken.Literal
+	expression.Body = p.parseBlock()
 
-	if p.peekTokenIs(token.COMMA) {
+	return expression
+}
 
-		p.nextToken()
+// parseForEachHeader parses a foreach up to its value, the body is left to
+// the caller.
+func (p *Parser) parseForEachHeader() *ast.Foreach {
+	expression := &ast.Foreach{Token: p.curToken}
+
+	p.nextToken()
+	target := p.parseExpression(LOWEST)
 
-		if !p.peekTokenIs(token.IDENT) {
+	if p.peekTokenIs(token.COMMA) {
+		index, ok := target.(*ast.Identifier)
+		if !ok {
 			p.errors = append(p.errors, fmt.Sprintf(
-				"%d:%d: second argument to foreach must be ident, got %v",
-				p.peekToken.LineNumber,
-				p.peekToken.LinePosition,
-				p.peekToken))
+				"%d:%d: first argument to foreach must be ident, got %v",
+				p.curToken.LineNumber,
+				p.curToken.LinePosition,
+				target))
 			return nil
 		}
-		p.nextToken()
+		expression.Index = index.Value
 
-		expression.Index = expression.Ident
-		expression.Ident = p.curToken.Literal
+		p.nextToken()
+		p.nextToken()
+		target = p.parseExpression(LOWEST)
+	}
 
+	if ident, ok := target.(*ast.Identifier); ok {
+		expression.Ident = ident.Value
+	} else if isDestructuring(target) && p.checkPattern(target) {
+		expression.Pattern = target
+	} else {
+		p.errors = append(p.errors, fmt.Sprintf(
+			"%d:%d: value of foreach must be ident or a destructuring pattern, got %v",
+			p.curToken.LineNumber,
+			p.curToken.LinePosition,
+			target))
+		return nil
 	}
 
 	if !p.expectPeek(token.IN) {
@@ -37,7 +62,10 @@ func (p *Parser) parseForEach() ast.Expression {
 	}
 	p.nextToken()
 
+	outerNoBlocks := p.noBlocks
+	p.noBlocks = true
 	expression.Value = p.parseExpression(LOWEST)
+	p.noBlocks = outerNoBlocks
 	if expression.Value == nil {
 		return nil
 	}
@@ -52,8 +80,5 @@ func (p *Parser) parseForEach() ast.Expression {
 		return nil
 	}
 
-	p.nextToken()
-	expression.Body = p.parseBlock()
-
 	return expression
 }
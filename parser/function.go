@@ -18,21 +18,116 @@ func (p *Parser) parseFunction() ast.Expression {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	lit.Parameters = p.parseFunctionParameters(token.RPAREN)
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
-	lit.Body = p.parseBlock()
+	p.parseFunctionBody(lit)
+
+	return lit
+}
+
+// parseArrowFunction parses a lambda like `->(x) { x * 2 }`, the
+// parameters may be left out.
+func (p *Parser) parseArrowFunction() ast.Expression {
+	lit := &ast.Function{Token: p.curToken, Parameters: []*ast.Identifier{}}
+
+	if p.peekTokenIs(token.LPAREN) {
+		p.nextToken()
+		lit.Parameters = p.parseFunctionParameters(token.RPAREN)
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	p.parseFunctionBody(lit)
+
+	return lit
+}
+
+// parseLambda parses a lambda like `|x, y| x + y` whose body is a single
+// expression. `|| x` takes no parameters.
+func (p *Parser) parseLambda() ast.Expression {
+	lit := &ast.Function{Token: p.curToken, Parameters: []*ast.Identifier{}}
+
+	if p.curTokenIs(token.BIT_OR) {
+		lit.Parameters = p.parseFunctionParameters(token.BIT_OR)
+	}
+
+	p.nextToken()
+	lit.Body = &ast.Block{Token: p.curToken}
+
+	outerYieldSeen := p.yieldSeen
+	p.yieldSeen = false
+
+	expression := p.parseExpression(LOWEST)
+	if expression == nil {
+		return nil
+	}
+	lit.Body.Statements = []ast.Statement{&ast.ExpressionStatement{Token: lit.Body.Token, Expression: expression}}
+	lit.Generator = p.yieldSeen
+	if !lit.Generator {
+		markTailCalls(lit.Body, true)
+	}
+
+	p.yieldSeen = outerYieldSeen
 
 	return lit
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+// parseBlockArgument parses a trailing block like `{ |x| puts(x) }` or
+// `do |x| puts(x) end`, the current token is the opening brace or do.
+func (p *Parser) parseBlockArgument() *ast.Function {
+	lit := &ast.Function{Token: p.curToken, Parameters: []*ast.Identifier{}, Block: true}
+
+	if p.peekTokenIs(token.BIT_OR) {
+		p.nextToken()
+		lit.Parameters = p.parseFunctionParameters(token.BIT_OR)
+	} else if p.peekTokenIs(token.OR) {
+		p.nextToken()
+	}
+
+	p.parseFunctionBody(lit)
+	lit.Body.Token = lit.Token
+
+	return lit
+}
+
+// peekStartsBlock reports whether a trailing block follows the current
+// token. A block in braces has to start on the same line.
+func (p *Parser) peekStartsBlock() bool {
+	if p.peekTokenIs(token.DO) {
+		return true
+	}
+
+	return !p.noBlocks && p.peekTokenIs(token.LBRACE) && p.peekToken.LineNumber == p.curToken.EndLineNumber
+}
+
+// parseFunctionBody parses the block of a function and records whether it
+// contains a yield.
+func (p *Parser) parseFunctionBody(lit *ast.Function) {
+	outerYieldSeen := p.yieldSeen
+	outerNoBlocks := p.noBlocks
+	p.yieldSeen = false
+	p.noBlocks = false
+
+	lit.Body = p.parseBlock()
+	lit.Generator = p.yieldSeen
+	if !lit.Generator {
+		markTailCalls(lit.Body, true)
+	}
+
+	p.yieldSeen = outerYieldSeen
+	p.noBlocks = outerNoBlocks
+}
+
+func (p *Parser) parseFunctionParameters(end token.TokenType) []*ast.Identifier {
 	identifiers := []*ast.Identifier{}
 
-	if p.peekTokenIs(token.RPAREN) {
+	if p.peekTokenIs(end) {
 		p.nextToken()
 		return identifiers
 	}
@@ -49,7 +144,7 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 		identifiers = append(identifiers, ident)
 	}
 
-	if !p.expectPeek(token.RPAREN) {
+	if !p.expectPeek(end) {
 		return nil
 	}
 
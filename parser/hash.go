@@ -6,13 +6,44 @@ import (
 )
 
 func (p *Parser) parseHash() ast.Expression {
+	defer p.nest()()
 	hash := &ast.Hash{Token: p.curToken}
-	hash.Pairs = make(map[ast.Expression]ast.Expression)
+	hash.Pairs = []ast.HashPair{}
 
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
+
+		if p.curTokenIs(token.POWER) {
+			splat := &ast.Splat{Token: p.curToken}
+			p.nextToken()
+			splat.Value = p.parseExpression(LOWEST)
+			hash.Pairs = append(hash.Pairs, ast.HashPair{Key: splat})
+
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
+
 		key := p.parseExpression(LOWEST)
 
+		// `{name}` is short for `{name: name}`
+		if ident, ok := key.(*ast.Identifier); ok && (p.peekTokenIs(token.COMMA) || p.peekTokenIs(token.RBRACE)) {
+			hash.Pairs = append(hash.Pairs, ast.HashPair{Key: symbolKey(ident), Value: ident})
+
+			if !p.peekTokenIs(token.RBRACE) {
+				p.nextToken()
+			}
+			continue
+		}
+
+		// `{name: 1}` is short for `{:name: 1}`, `{(name): 1}` uses the
+		// variable as key
+		ident, isName := key.(*ast.Identifier)
+		isName = isName && p.curTokenIs(token.IDENT)
+
+		// the lexer reads the colon of `{"a" :b}` as a symbol
+		p.splitPeekSymbol()
 		if !p.expectPeek(token.COLON) {
 			return nil
 		}
@@ -20,7 +51,16 @@ func (p *Parser) parseHash() ast.Expression {
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
 
-		hash.Pairs[key] = value
+		// `{k: v foreach k, v in h}`
+		if len(hash.Pairs) == 0 && p.peekTokenIs(token.FOREACH) {
+			return p.parseComprehension(hash.Token, key, value, token.RBRACE)
+		}
+
+		if isName {
+			key = symbolKey(ident)
+		}
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -34,3 +74,9 @@ func (p *Parser) parseHash() ast.Expression {
 
 	return hash
 }
+
+// symbolKey returns the symbol key of a hash literal written as a name. It
+// keeps the token of the name, so the hash is printed like it was written.
+func symbolKey(ident *ast.Identifier) *ast.Symbol {
+	return &ast.Symbol{Token: ident.Token, Value: ident.Value}
+}
@@ -7,32 +7,92 @@ import (
 
 func (p *Parser) parseIf() ast.Expression {
 	expression := &ast.If{Token: p.curToken}
-	if !p.expectPeek(token.LPAREN) {
+
+	expression.Condition, expression.Consequence = p.parseConditionalBlock()
+	if expression.Consequence == nil {
+		return nil
+	}
+
+	expression.Alternative = p.parseAlternative(true)
+	return expression
+}
+
+func (p *Parser) parseUnless() ast.Expression {
+	expression := &ast.Unless{Token: p.curToken}
+
+	expression.Condition, expression.Consequence = p.parseConditionalBlock()
+	if expression.Consequence == nil {
 		return nil
 	}
 
+	expression.Alternative = p.parseAlternative(false)
+	return expression
+}
+
+// parseConditionalBlock parses `(condition)` followed by a body.
+func (p *Parser) parseConditionalBlock() (ast.Expression, *ast.Block) {
+	if !p.expectPeek(token.LPAREN) {
+		return nil, nil
+	}
+
 	p.nextToken()
-	expression.Condition = p.parseExpression(LOWEST)
+	condition := p.parseExpression(LOWEST)
 
 	if !p.expectPeek(token.RPAREN) {
-		return nil
+		return nil, nil
 	}
 
-	if p.peekTokenIs(token.LBRACE) {
-		p.nextToken()
+	return condition, p.parseBody()
+}
+
+// parseBody parses either a block in braces or a block ending with else,
+// elsif or end. After braces the closing brace stays the current token
+// unless an else or elsif follows.
+func (p *Parser) parseBody() *ast.Block {
+	if !p.peekTokenIs(token.LBRACE) {
+		return p.parseBlock()
 	}
-	expression.Consequence = p.parseBlock()
 
-	if p.curTokenIs(token.RBRACE) {
+	p.nextToken()
+	block := p.parseBlock()
+
+	if p.curTokenIs(token.RBRACE) && (p.peekTokenIs(token.ELSE) || p.peekTokenIs(token.ELSIF)) {
 		p.nextToken()
 	}
 
-	if p.curTokenIs(token.ELSE) {
+	return block
+}
 
-		if p.peekTokenIs(token.LBRACE) {
+// parseAlternative parses the else branch following a consequence. With
+// elsif set, `elsif` and `else if` on the same line continue the chain
+// and share its end.
+func (p *Parser) parseAlternative(elsif bool) *ast.Block {
+	if elsif && (p.curTokenIs(token.ELSIF) || p.curTokenIs(token.ELSE) && p.peekTokenIs(token.IF) && p.peekToken.LineNumber == p.curToken.EndLineNumber) {
+		if p.curTokenIs(token.ELSE) {
 			p.nextToken()
 		}
-		expression.Alternative = p.parseBlock()
+
+		tok := p.curToken
+		tok.Type = token.ELSIF
+
+		nested := p.parseIf()
+		if nested == nil {
+			return nil
+		}
+		nested.(*ast.If).Token = tok
+
+		return &ast.Block{
+			Token:      tok,
+			Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, Expression: nested}},
+		}
 	}
-	return expression
+
+	if !p.curTokenIs(token.ELSE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+	}
+	return p.parseBlock()
 }
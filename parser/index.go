@@ -6,8 +6,10 @@ import (
 )
 
 func (p *Parser) parseIndex(left ast.Expression) ast.Expression {
+	defer p.nest()()
 	exp := &ast.Index{Token: p.curToken, Left: left}
 
+	p.splitPeekSymbol()
 	p.nextToken()
 
 	if p.curTokenIs(token.COLON) {
@@ -17,6 +19,7 @@ func (p *Parser) parseIndex(left ast.Expression) ast.Expression {
 
 	exp.Index = p.parseExpression(LOWEST)
 
+	p.splitPeekSymbol()
 	if p.peekTokenIs(token.COLON) {
 		p.nextToken()
 		return p.parseRangeIndex(exp)
@@ -6,15 +6,25 @@ import (
 )
 
 func (p *Parser) parseMethodCall(obj ast.Expression) ast.Expression {
-	if _, ok := p.imports[obj.String()]; ok {
-		p.expectPeek(token.IDENT)
-		index := &ast.String{Token: p.curToken, Value: p.curToken.Literal}
-		return &ast.Index{Left: obj, Index: index}
-	}
-
-	methodCall := &ast.ObjectCall{Token: p.curToken, Object: obj}
+	methodCall := &ast.ObjectCall{Token: p.curToken, Object: obj, Safe: p.curTokenIs(token.SAFE_PERIOD)}
 	p.nextToken()
 	name := p.parseIdentifier()
+
+	// without parentheses it is a property access like `a.size`
+	if !p.peekTokenIs(token.LPAREN) {
+		if !p.peekStartsBlock() {
+			methodCall.Call = name
+			return methodCall
+		}
+
+		// `a.each { |x| ... }` calls the method with only the block
+		p.nextToken()
+		call := &ast.Call{Token: p.curToken, Callable: name}
+		call.Arguments = []ast.Expression{p.parseBlockArgument()}
+		methodCall.Call = call
+		return methodCall
+	}
+
 	p.nextToken()
 	methodCall.Call = p.parseCall(name)
 	return methodCall
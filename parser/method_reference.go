@@ -0,0 +1,17 @@
+package parser
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/token"
+)
+
+func (p *Parser) parseMethodReference() ast.Expression {
+	reference := &ast.MethodReference{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	reference.Name = p.curToken.Literal
+
+	return reference
+}
@@ -13,33 +13,64 @@ const (
 	LOWEST
 	ASSIGN      //=
 	TERNARY     // ? :
+	COALESCE    // ??
+	LOGICAL_OR  // ||
+	LOGICAL_AND // &&
 	EQUALS      //==
 	LESSGREATER // > or <
+	PIPE        // |>
+	BIT_OR      // | or ^
+	BIT_AND     // &
+	SHIFT       // << or >>
 	SUM         // +
 	PRODUCT     // *
 	MODULO      // %
 	PREFIX      // -X or !X
+	POWER       // **
 	CALL        // myFcuntion(X)
 	INDEX       // array[index]
 )
 
 var precedences = map[token.TokenType]int{
-	token.ASSIGN:   ASSIGN,
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.LT_EQ:    LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.GT_EQ:    LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.PERCENT:  MODULO,
-	token.QUESTION: TERNARY,
-	token.LPAREN:   CALL,
-	token.PERIOD:   CALL,
-	token.LBRACKET: INDEX,
+	token.ASSIGN:          ASSIGN,
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.PERCENT_ASSIGN:  ASSIGN,
+	token.OR:              LOGICAL_OR,
+	token.AND:             LOGICAL_AND,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.MATCH:           EQUALS,
+	token.LT:              LESSGREATER,
+	token.LT_EQ:           LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.GT_EQ:           LESSGREATER,
+	token.PIPE:            PIPE,
+	token.BIT_OR:          BIT_OR,
+	token.BIT_XOR:         BIT_OR,
+	token.BIT_AND:         BIT_AND,
+	token.SHIFT_LEFT:      SHIFT,
+	token.SHIFT_RIGHT:     SHIFT,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.PERCENT:         MODULO,
+	token.POWER:           POWER,
+	token.QUESTION:        TERNARY,
+	token.NULL_COALESCE:   COALESCE,
+	token.LPAREN:          CALL,
+	token.PERIOD:          CALL,
+	token.SAFE_PERIOD:     CALL,
+	token.LBRACKET:        INDEX,
+}
+
+// rightAssociative lists the infix operators which bind to the right,
+// e.g. `2 ** 3 ** 2` is parsed as `2 ** (3 ** 2)`.
+var rightAssociative = map[token.TokenType]bool{
+	token.POWER: true,
 }
 
 type (
@@ -55,17 +86,35 @@ type Parser struct {
 	curToken  token.Token
 	peekToken token.Token
 
+	// pending is the token after peekToken if a symbol was split into a
+	// colon and a name, see splitPeekSymbol
+	pending *token.Token
+
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
 
-	imports map[string]struct{}
+	// globals are the variables of the program, including the ones of
+	// programs parsed before, like earlier lines of a REPL
+	globals []string
+
+	// yieldSeen is set once a yield is parsed in the current function body
+	yieldSeen bool
+
+	// noBlocks disables trailing blocks in braces where a body follows,
+	// e.g. in `foreach i in f() { ... }`
+	noBlocks bool
+
+	// statementLevel is set while parsing the expression of a statement
+	// outside of any brackets, where a `[` or `*` on a new line starts the
+	// next statement, see peekStartsPattern
+	statementLevel bool
 }
 
-func New(l *lexer.Lexer, imports map[string]struct{}) *Parser {
+func New(l *lexer.Lexer, globals []string) *Parser {
 	p := &Parser{
 		l:       l,
 		errors:  []string{},
-		imports: imports,
+		globals: globals,
 	}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
@@ -74,28 +123,55 @@ func New(l *lexer.Lexer, imports map[string]struct{}) *Parser {
 	p.registerPrefix(token.FLOAT, p.parseFloat)
 	p.registerPrefix(token.BANG, p.parsePrefix)
 	p.registerPrefix(token.MINUS, p.parsePrefix)
+	p.registerPrefix(token.ASTERISK, p.parseSplat)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NULL, p.parseNull)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIf)
+	p.registerPrefix(token.UNLESS, p.parseUnless)
 	p.registerPrefix(token.FOREACH, p.parseForEach)
 	p.registerPrefix(token.WHILE, p.parseWhile)
+	p.registerPrefix(token.UNTIL, p.parseUntil)
+	p.registerPrefix(token.LOOP, p.parseLoop)
 	p.registerPrefix(token.FUNCTION, p.parseFunction)
+	p.registerPrefix(token.ARROW, p.parseArrowFunction)
+	p.registerPrefix(token.BIT_OR, p.parseLambda)
+	p.registerPrefix(token.OR, p.parseLambda)
+	p.registerPrefix(token.METHOD_REF, p.parseMethodReference)
 	p.registerPrefix(token.STRING, p.parseString)
+	p.registerPrefix(token.SYMBOL, p.parseSymbol)
 	p.registerPrefix(token.LBRACKET, p.parseArray)
 	p.registerPrefix(token.LBRACE, p.parseHash)
 	p.registerPrefix(token.IMPORT, p.parseImport)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PERCENT_ASSIGN, p.parseAssignExpression)
 	p.registerInfix(token.PLUS, p.parseInfix)
 	p.registerInfix(token.MINUS, p.parseInfix)
 	p.registerInfix(token.SLASH, p.parseInfix)
 	p.registerInfix(token.ASTERISK, p.parseInfix)
 	p.registerInfix(token.PERCENT, p.parseInfix)
+	p.registerInfix(token.POWER, p.parseInfix)
+	p.registerInfix(token.AND, p.parseInfix)
+	p.registerInfix(token.OR, p.parseInfix)
+	p.registerInfix(token.NULL_COALESCE, p.parseInfix)
+	p.registerInfix(token.BIT_AND, p.parseInfix)
+	p.registerInfix(token.BIT_OR, p.parseInfix)
+	p.registerInfix(token.PIPE, p.parseInfix)
+	p.registerInfix(token.BIT_XOR, p.parseInfix)
+	p.registerInfix(token.SHIFT_LEFT, p.parseInfix)
+	p.registerInfix(token.SHIFT_RIGHT, p.parseInfix)
 	p.registerInfix(token.EQ, p.parseInfix)
 	p.registerInfix(token.NOT_EQ, p.parseInfix)
+	p.registerInfix(token.MATCH, p.parseInfix)
 	p.registerInfix(token.PERIOD, p.parseMethodCall)
+	p.registerInfix(token.SAFE_PERIOD, p.parseMethodCall)
 	p.registerInfix(token.LT, p.parseInfix)
 	p.registerInfix(token.LT_EQ, p.parseInfix)
 	p.registerInfix(token.GT, p.parseInfix)
@@ -128,13 +204,53 @@ func (p *Parser) noPrefixParseFnError(t token.Token) {
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
+	if p.pending != nil {
+		p.peekToken = *p.pending
+		p.pending = nil
+		return
+	}
 	p.peekToken = p.l.NextToken()
 }
 
+// splitPeekSymbol turns a symbol in the next token into a colon followed
+// by a name. Where a colon is expected, like in `c ? 1 :n` or `s[i :n]`,
+// the lexer can not know that the colon does not start a symbol.
+func (p *Parser) splitPeekSymbol() {
+	if !p.peekTokenIs(token.SYMBOL) {
+		return
+	}
+
+	symbol := p.peekToken
+	name := symbol.Literal[1:]
+	p.peekToken = token.Token{
+		Type:            token.COLON,
+		Literal:         ":",
+		LineNumber:      symbol.LineNumber,
+		LinePosition:    symbol.LinePosition,
+		EndLineNumber:   symbol.LineNumber,
+		EndLinePosition: symbol.LinePosition + 1,
+	}
+	p.pending = &token.Token{
+		Type:            token.LookupIdent(name),
+		Literal:         name,
+		LineNumber:      symbol.LineNumber,
+		LinePosition:    symbol.LinePosition + 1,
+		EndLineNumber:   symbol.EndLineNumber,
+		EndLinePosition: symbol.EndLinePosition,
+	}
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 
+	outerStatementLevel := p.statementLevel
+	p.statementLevel = true
 	stmt.Expression = p.parseExpression(LOWEST)
+	p.statementLevel = outerStatementLevel
+
+	if p.peekTokenIs(token.COMMA) {
+		stmt.Expression = p.parseMultipleAssign(stmt.Expression)
+	}
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
@@ -11,8 +11,7 @@ import (
 
 func createProgram(input string) (*ast.Program, *Parser) {
 	l := lexer.New(input)
-	imports := make(map[string]struct{})
-	p := New(l, imports)
+	p := New(l, nil)
 	program, _ := p.ParseProgram()
 
 	return program, p
@@ -43,6 +42,40 @@ return 993322;
 	}
 }
 
+func TestGeneratorFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input     string
+		generator bool
+	}{
+		{`def () { yield 1; }`, true},
+		{`def () { if (true) { yield 1 } }`, true},
+		{`def () { return 1; }`, false},
+		{`def () { def () { yield 1 } }`, false},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		function, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Function)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.Function. got=%T", program.Statements[0])
+		}
+		if function.Generator != tt.generator {
+			t.Errorf("function.Generator wrong for %q. want=%t, got=%t", tt.input, tt.generator, function.Generator)
+		}
+	}
+
+	program, p := createProgram(`def () { yield 5; }`)
+	checkParserErrors(t, p)
+	body := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Function).Body
+	yield, ok := body.Statements[0].(*ast.Yield)
+	if !ok {
+		t.Fatalf("stmt is not ast.Yield. got=%T", body.Statements[0])
+	}
+	testLiteralExpression(t, yield.Value, 5)
+}
+
 func checkParserErrors(t *testing.T, p *Parser) {
 	errors := p.Errors()
 	if len(errors) == 0 {
@@ -140,6 +173,51 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestNumberLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"0xFF", int64(255)},
+		{"0b1010", int64(10)},
+		{"0o755", int64(493)},
+		{"1_000_000", int64(1000000)},
+		{"1.5e-3", 0.0015},
+		{"2E3", 2000.0},
+		{"1_000.5", 1000.5},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		switch expected := tt.expected.(type) {
+		case int64:
+			integer, ok := stmt.Expression.(*ast.Integer)
+			if !ok || integer.Value != expected {
+				t.Errorf("%q: expected integer %d, got %s", tt.input, expected, stmt.Expression)
+			}
+		case float64:
+			float, ok := stmt.Expression.(*ast.Float)
+			if !ok || float.Value != expected {
+				t.Errorf("%q: expected float %g, got %s", tt.input, expected, stmt.Expression)
+			}
+		}
+	}
+}
+
+func TestInvalidNumberLiterals(t *testing.T) {
+	tests := []string{"0x", "0b102", "1__0", "1_", "1.5_"}
+
+	for _, input := range tests {
+		_, p := createProgram(input)
+		if len(p.Errors()) == 0 {
+			t.Errorf("%q: expected a parser error", input)
+		}
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -390,6 +468,42 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"a || b && c",
+			"(a || (b && c))",
+		},
+		{
+			"a == b && c != d || e",
+			"(((a == b) && (c != d)) || e)",
+		},
+		{
+			"a & b == c",
+			"((a & b) == c)",
+		},
+		{
+			"a | b ^ c & d",
+			"((a | b) ^ (c & d))",
+		},
+		{
+			"a << 1 + b",
+			"(a << (1 + b))",
+		},
+		{
+			"a ** b ** c",
+			"(a ** (b ** c))",
+		},
+		{
+			"-a ** b",
+			"(-(a ** b))",
+		},
+		{
+			"a * b ** c",
+			"(a * (b ** c))",
+		},
+		{
+			"a += b * c",
+			"a += (b * c)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -465,6 +579,333 @@ func TestIfExpression(t *testing.T) {
 	}
 }
 
+func TestElsifParsing(t *testing.T) {
+	tests := []string{
+		"if (a)\n 1\nelsif (b)\n 2\nelse\n 3\nend",
+		"if (a)\n 1\nelse if (b)\n 2\nelse\n 3\nend",
+		"if (a) { 1 } elsif (b) { 2 } else { 3 }",
+		"if (a) { 1 } else if (b) { 2 } else { 3 }",
+	}
+
+	for _, input := range tests {
+		program, p := createProgram(input)
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("%q: expected 1 statement, got=%d", input, len(program.Statements))
+		}
+
+		exp := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.If)
+		elsif := exp.Elsif()
+		if elsif == nil {
+			t.Fatalf("%q: alternative is not an elsif. got=%s", input, exp.Alternative)
+		}
+		if !testIdentifier(t, elsif.Condition, "b") {
+			return
+		}
+		if elsif.Alternative == nil || elsif.Elsif() != nil {
+			t.Errorf("%q: expected else block after elsif, got=%s", input, elsif.Alternative)
+		}
+	}
+
+	// an if on the line after else is nested and needs its own end
+	program, p := createProgram("if (a)\n 1\nelse\n if (b)\n 2\n end\nend")
+	checkParserErrors(t, p)
+	if exp := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.If); exp.Elsif() != nil {
+		t.Errorf("expected else block with nested if, got elsif")
+	}
+}
+
+func TestModifierParsing(t *testing.T) {
+	tests := []struct {
+		input      string
+		statements int
+		modifier   string
+	}{
+		{"puts(x) if debug", 1, "if"},
+		{"puts(x) unless debug", 1, "unless"},
+		{"i = i + 1 while i < 10", 1, "while"},
+		{"i = i + 1 until i == 10", 1, "until"},
+		{"return 1 if a", 1, "if"},
+		{"a = 1\nif (b) { 2 }", 2, ""},
+		{"a = 1; while (b) { 2 }", 2, ""},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != tt.statements {
+			t.Fatalf("%q: expected %d statements, got=%d", tt.input, tt.statements, len(program.Statements))
+		}
+
+		modifier, ok := program.Statements[0].(*ast.Modifier)
+		if tt.modifier == "" {
+			if ok {
+				t.Errorf("%q: unexpected modifier %s", tt.input, modifier)
+			}
+			continue
+		}
+		if !ok {
+			t.Fatalf("%q: statement is not ast.Modifier. got=%T", tt.input, program.Statements[0])
+		}
+		if modifier.TokenLiteral() != tt.modifier {
+			t.Errorf("%q: wrong modifier. want=%s, got=%s", tt.input, tt.modifier, modifier.TokenLiteral())
+		}
+	}
+}
+
+func TestBracedBlocksKeepFollowingStatements(t *testing.T) {
+	tests := []struct {
+		input      string
+		statements int
+	}{
+		{"if (a) { 1 }\nputs(2)", 2},
+		{"while (a) { 1 }\nputs(2)", 2},
+		{"def () { if (a) { 1 } }\nputs(2)", 2},
+		{"def () { while (a) { 1 } }\nputs(2)", 2},
+		{"loop { 1 }\nputs(2)", 2},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != tt.statements {
+			t.Errorf("%q: expected %d statements, got=%d", tt.input, tt.statements, len(program.Statements))
+		}
+	}
+}
+
+func TestBlockParsing(t *testing.T) {
+	tests := []struct {
+		input     string
+		arguments int
+		params    int
+	}{
+		{"f(1) { |x| x }", 2, 1},
+		{"f() do |a, b| a end", 1, 2},
+		{"a.each { |x| puts(x) }", 1, 1},
+		{"a.each() do puts(1) end", 1, 0},
+		{"f(1) { || 2 }", 2, 0},
+		{"foreach i in (f() { |x| x }) { i }", 1, 1},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		exp := program.Statements[0].(*ast.ExpressionStatement).Expression
+		if foreach, ok := exp.(*ast.Foreach); ok {
+			exp = foreach.Value
+		}
+		if objectCall, ok := exp.(*ast.ObjectCall); ok {
+			exp = objectCall.Call
+		}
+		call, ok := exp.(*ast.Call)
+		if !ok {
+			t.Fatalf("%q: expression is not ast.Call. got=%T", tt.input, exp)
+		}
+		if len(call.Arguments) != tt.arguments {
+			t.Errorf("%q: expected %d arguments, got=%d", tt.input, tt.arguments, len(call.Arguments))
+		}
+		block := call.Block()
+		if block == nil {
+			t.Fatalf("%q: call has no block", tt.input)
+		}
+		if len(block.Parameters) != tt.params {
+			t.Errorf("%q: expected %d block parameters, got=%d", tt.input, tt.params, len(block.Parameters))
+		}
+	}
+
+	noBlocks := []struct {
+		input      string
+		statements int
+	}{
+		{"foreach i in f() { puts(i) }", 1},
+		{"f()\n{a} = h", 2},
+		{"a.b\n{c: 1}", 2},
+		{"g = |x| x * 2", 1},
+		{"g = ->(x) { x }", 1},
+	}
+
+	for _, tt := range noBlocks {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != tt.statements {
+			t.Errorf("%q: expected %d statements, got=%d", tt.input, tt.statements, len(program.Statements))
+		}
+		if strings.Contains(program.String(), "{ |") {
+			t.Errorf("%q: unexpected block in %q", tt.input, program.String())
+		}
+	}
+}
+
+func TestTailCallMarking(t *testing.T) {
+	last := func(b *ast.Block) ast.Expression {
+		return b.Statements[len(b.Statements)-1].(*ast.ExpressionStatement).Expression
+	}
+
+	tests := []struct {
+		input string
+		call  func(*ast.Block) ast.Expression
+		tail  bool
+	}{
+		{"def () { g() }", last, true},
+		{"def () { g(); 1 }", func(b *ast.Block) ast.Expression { return b.Statements[0].(*ast.ExpressionStatement).Expression }, false},
+		{"def () { return g() }", func(b *ast.Block) ast.Expression { return b.Statements[0].(*ast.Return).ReturnValue }, true},
+		{"def () { 1 + g() }", func(b *ast.Block) ast.Expression { return last(b).(*ast.Infix).Right }, false},
+		{"def () { g(h()) }", func(b *ast.Block) ast.Expression { return last(b).(*ast.Call).Arguments[0] }, false},
+		{"def () { if (a) { g() } else { h() } }", func(b *ast.Block) ast.Expression { return last(last(b).(*ast.If).Alternative) }, true},
+		{"def () { if (a) { g() }; 1 }", func(b *ast.Block) ast.Expression {
+			return last(b.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.If).Consequence)
+		}, false},
+		{"def () { a ? g() : h() }", func(b *ast.Block) ast.Expression { return last(b).(*ast.Ternary).Consequence }, true},
+		{"def () { while (a) { g() } }", func(b *ast.Block) ast.Expression { return last(last(b).(*ast.While).Body) }, false},
+		{"def () { g() if (a) }", func(b *ast.Block) ast.Expression {
+			return b.Statements[0].(*ast.Modifier).Statement.(*ast.ExpressionStatement).Expression
+		}, false},
+		{"def () { yield 1; g() }", last, false},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Function)
+		call, ok := tt.call(function.Body).(*ast.Call)
+		if !ok {
+			t.Fatalf("%q: expression is not ast.Call", tt.input)
+		}
+		if call.Tail != tt.tail {
+			t.Errorf("%q: expected tail=%t, got=%t", tt.input, tt.tail, call.Tail)
+		}
+	}
+}
+
+func TestComprehensionParsing(t *testing.T) {
+	tests := []struct {
+		input      string
+		key        bool
+		clauses    int
+		conditions int
+	}{
+		{"[x foreach x in xs]", false, 1, 0},
+		{"[x foreach x in xs if x > 1]", false, 1, 1},
+		{"[[x, y] foreach x in a if x foreach y in b if y]", false, 2, 2},
+		{"{k: v foreach k, v in h}", true, 1, 0},
+		{"[a foreach [a, b] in xs foreach c in 3 if c]", false, 2, 1},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		comprehension, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Comprehension)
+		if !ok {
+			t.Fatalf("%q: expression is not ast.Comprehension. got=%T", tt.input, program.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if (comprehension.Key != nil) != tt.key {
+			t.Errorf("%q: wrong key. got=%v", tt.input, comprehension.Key)
+		}
+		if len(comprehension.Clauses) != tt.clauses {
+			t.Errorf("%q: expected %d clauses, got=%d", tt.input, tt.clauses, len(comprehension.Clauses))
+		}
+		conditions := 0
+		for _, clause := range comprehension.Clauses {
+			if clause.Condition != nil {
+				conditions++
+			}
+		}
+		if conditions != tt.conditions {
+			t.Errorf("%q: expected %d conditions, got=%d", tt.input, tt.conditions, conditions)
+		}
+	}
+
+	for _, input := range []string{"[x foreach x in xs, 1]", "{k: v, 1: 2 foreach k, v in h}", "[x foreach 1 in xs]"} {
+		_, p := createProgram(input)
+		if len(p.Errors()) == 0 {
+			t.Errorf("%q: expected a parser error", input)
+		}
+	}
+}
+
+func TestPropertyParsing(t *testing.T) {
+	program, p := createProgram("a.b = a.size")
+	checkParserErrors(t, p)
+
+	assign, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Assign)
+	if !ok {
+		t.Fatalf("expression is not ast.Assign. got=%T", program.Statements[0])
+	}
+	if _, ok := assign.Name.(*ast.ObjectCall).Call.(*ast.Identifier); !ok {
+		t.Errorf("assign target is not a property. got=%T", assign.Name.(*ast.ObjectCall).Call)
+	}
+	if _, ok := assign.Value.(*ast.ObjectCall).Call.(*ast.Identifier); !ok {
+		t.Errorf("value is not a property. got=%T", assign.Value.(*ast.ObjectCall).Call)
+	}
+
+	for _, input := range []string{"a.b() = 1", "a&.b = 1"} {
+		_, p := createProgram(input)
+		if len(p.Errors()) == 0 {
+			t.Errorf("%q: expected a parser error", input)
+		}
+	}
+}
+
+func TestDestructuringParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a, b = 1, 2", "[a, b] = [1, 2]"},
+		{"a, *b = c", "[a, *b] = c"},
+		{"[a, [b, *c]] = d", "[a, [b, *c]] = d"},
+		{"a, b.c, d[0] = e", "[a, b.c, (d[0])] = e"},
+		{"return 1, 2", "return ([1, 2])"},
+		{"foreach [a, b] in c { a }", "foreach [a, b] in c {\n  a\n}"},
+		{"foreach i, [a, b] in c { a }", "foreach i, [a, b] in c {\n  a\n}"},
+		{"a = 1\n[b] = c", "a = 1[b] = c"},
+		{"a = 1\n*b, c = d", "a = 1[*b, c] = d"},
+		{"x = (2\n * 3)", "x = (2 * 3)"},
+		{"x = (a\n[0])", "x = (a[0])"},
+		{"f(a\n[0], 2\n* 3)", "f((a[0]), (2 * 3))"},
+		{"x = [a\n[0]]", "x = [(a[0])]"},
+		{"x = {\"k\": a\n* 2}", "x = {\"k\":(a * 2)}"},
+		{"f(-> {\n a = 1\n [b] = c\n})", "f(->() a = 1[b] = c)"},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{"a, 1 = b", "cannot assign to 1"},
+		{"a, f() = b", "cannot assign to f()"},
+		{"*a, *b = c", "only one splat is allowed in [*a, *b]"},
+		{"[a, b] += c", "cannot use += to destructure"},
+		{"{**a} = b", "cannot assign to **a"},
+		{"foreach 1, a in b { a }", "first argument to foreach must be ident, got 1"},
+		{"foreach a + 1 in b { a }", "value of foreach must be ident or a destructuring pattern, got (a + 1)"},
+	}
+
+	for _, tt := range errors {
+		_, p := createProgram(tt.input)
+		if len(p.Errors()) == 0 || !strings.Contains(p.Errors()[0], tt.expected) {
+			t.Errorf("%q: expected error %q, got=%v", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := `def (x, y) { x + y; }`
 
@@ -581,6 +1022,60 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestStringDelimiters(t *testing.T) {
+	tests := []struct {
+		input     string
+		value     string
+		delimiter string
+	}{
+		{`"a\tb\n\"c\" \\ \d"`, "a\tb\n\"c\" \\ \\d", `"`},
+		{`'a\tb \'`, `a\tb \`, "'"},
+		{"\"\"\"\n{\"a\": 1}\n\"\"\"", "{\"a\": 1}\n", `"""`},
+		{"<<~EOS\n    a\n      b\n\n    c\\t\n  EOS", "a\n  b\n\nc\t\n", "<<~EOS"},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.String)
+		if !ok {
+			t.Fatalf("exp not *ast.String. got=%T", stmt.Expression)
+		}
+
+		if literal.Value != tt.value {
+			t.Errorf("%q: literal.Value not %q. got=%q", tt.input, tt.value, literal.Value)
+		}
+		if literal.Delimiter != tt.delimiter {
+			t.Errorf("%q: literal.Delimiter not %q. got=%q", tt.input, tt.delimiter, literal.Delimiter)
+		}
+		if literal.String() != tt.input {
+			t.Errorf("%q: literal.String() not the input. got=%q", tt.input, literal.String())
+		}
+	}
+}
+
+func TestUnterminatedStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x = "abc`, `0:5: string is not terminated, expected "`},
+		{`x = "abc\"`, `0:5: string is not terminated, expected "`},
+		{`x = 'abc`, `0:5: string is not terminated, expected '`},
+		{`x = """abc"`, `0:5: string is not terminated, expected """`},
+		{"x = <<~EOS\n  abc", "0:5: string is not terminated, expected a line with EOS"},
+	}
+
+	for _, tt := range tests {
+		_, p := createProgram(tt.input)
+		if len(p.Errors()) != 1 || p.Errors()[0] != tt.expected {
+			t.Errorf("%q: expected error %q, got %q", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
 func TestParsingArray(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 
@@ -645,15 +1140,77 @@ func TestParsingHashLiteralsStringKeys(t *testing.T) {
 		"three": 3,
 	}
 
-	for key, value := range hash.Pairs {
-		literal, ok := key.(*ast.String)
+	for _, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*ast.String)
 		if !ok {
-			t.Errorf("key is not ast.StringLiteral. got=%T", key)
+			t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
 		}
 
-		expectedValue := expected[literal.String()]
+		expectedValue := expected[literal.Value]
+
+		testIntegerLiteral(t, pair.Value, expectedValue)
+	}
+}
+
+func TestColonBeforeName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"s[:n]", "(s[:n])"},
+		{"s[ :n]", "(s[:n])"},
+		{"s[1:n]", "(s[1:n])"},
+		{"s[i :n]", "(s[i:n])"},
+		{"s[i :nil]", "(s[i:null])"},
+		{"s[(:n)]", "(s[:n])"},
+		{"c ? 1 :n", "c ? 1 : n"},
+		{"c ? :a :n", "c ? :a : n"},
+		{"c ? :a : :n", "c ? :a : :n"},
+		{"[:a, :n]", "[:a, :n]"},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram("s = 1; n = 1; i = 1; c = 1; " + tt.input)
+		checkParserErrors(t, p)
+
+		if actual := program.Statements[len(program.Statements)-1].String(); actual != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestParsingHashLiteralsSymbolKeys(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{name: 1}", "*ast.Symbol"},
+		{"{:name: 1}", "*ast.Symbol"},
+		{"{name : 1}", "*ast.Symbol"},
+		{"{name :1}", "*ast.Symbol"},
+		{"{(name): 1}", "*ast.Identifier"},
+		{"{name}", "*ast.Symbol"},
+		{`{"a" :b}`, "*ast.String"},
+		{`{"a" :true}`, "*ast.String"},
+		{"{k: 1 foreach k in ks}", ""},
+	}
+
+	for _, tt := range tests {
+		program, p := createProgram(tt.input)
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		hash, ok := stmt.Expression.(*ast.Hash)
+		if !ok {
+			if _, ok := stmt.Expression.(*ast.Comprehension); !ok || tt.expected != "" {
+				t.Errorf("%q: expected a hash, got %T", tt.input, stmt.Expression)
+			}
+			continue
+		}
 
-		testIntegerLiteral(t, value, expectedValue)
+		if key := fmt.Sprintf("%T", hash.Pairs[0].Key); key != tt.expected {
+			t.Errorf("%q: expected key of type %s, got %s", tt.input, tt.expected, key)
+		}
 	}
 }
 
@@ -702,20 +1259,20 @@ func TestParsingHashLiteralWithExpressions(t *testing.T) {
 		},
 	}
 
-	for key, value := range hash.Pairs {
-		literal, ok := key.(*ast.String)
+	for _, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*ast.String)
 		if !ok {
-			t.Errorf("key is not ast.StringLiteral. got=%T", key)
+			t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
 			continue
 		}
 
-		testFunc, ok := tests[literal.String()]
+		testFunc, ok := tests[literal.Value]
 		if !ok {
-			t.Errorf("No test function for key %q found", literal.String())
+			t.Errorf("No test function for key %q found", literal.Value)
 			continue
 		}
 
-		testFunc(value)
+		testFunc(pair.Value)
 	}
 }
 
@@ -779,8 +1336,7 @@ func TestParsingImportExpressions(t *testing.T) {
 
 	for _, tt := range tests {
 		l := lexer.New(tt.input)
-		imports := make(map[string]struct{})
-		p := New(l, imports)
+		p := New(l, nil)
 		program, _ := p.ParseProgram()
 
 		checkParserErrors(t, p)
@@ -802,3 +1358,83 @@ func TestParsingForEachExpressionsFailsWithNegativeNumber(t *testing.T) {
 		t.Errorf("expected error that iterating over a negative number fails")
 	}
 }
+
+func TestResolvingVariables(t *testing.T) {
+	program, p := createProgram(`a = 1; def(b) { foreach c in b { a + b + c } }`)
+	checkParserErrors(t, p)
+
+	fn := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.Function)
+	loop := fn.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Foreach)
+	sum := loop.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Infix)
+	left := sum.Left.(*ast.Infix)
+
+	tests := []struct {
+		ident *ast.Identifier
+		depth int
+		slot  int
+	}{
+		{left.Left.(*ast.Identifier), 2, 0},
+		{left.Right.(*ast.Identifier), 1, 0},
+		{sum.Right.(*ast.Identifier), 0, 0},
+	}
+
+	for _, tt := range tests {
+		if !tt.ident.Resolved || tt.ident.Depth != tt.depth || tt.ident.Slot != tt.slot {
+			t.Errorf("%s: expected depth %d and slot %d, got resolved=%t depth=%d slot=%d", tt.ident, tt.depth, tt.slot, tt.ident.Resolved, tt.ident.Depth, tt.ident.Slot)
+		}
+	}
+
+	if fmt.Sprint(program.Locals.Names) != "[a]" || fmt.Sprint(fn.Locals.Names) != "[b]" || fmt.Sprint(loop.Locals.Names) != "[c]" {
+		t.Errorf("wrong locals. got=%v, %v, %v", program.Locals.Names, fn.Locals.Names, loop.Locals.Names)
+	}
+}
+
+func TestUndefinedVariables(t *testing.T) {
+	Predefined = func(name string) bool { return name == "puts" }
+	defer func() { Predefined = nil }()
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"puts(a)", []string{"0:6: undefined variable a"}},
+		{"a = 1; puts(a)", nil},
+		{"def f() { g() }; def g() { x }; f()", []string{"0:28: undefined variable x"}},
+		{"def f() { puts(later) }; later = 1", nil},
+		{"def f(x) { x }; x", []string{"0:17: undefined variable x"}},
+		{"foreach i, x in [1] { puts(i, x) }", nil},
+		{"while (true)\n  y = 1\nend\nputs(y)", []string{"3:6: undefined variable y"}},
+		{"[x * y foreach x in [1] foreach y in [x] if y > x]; x", []string{"0:53: undefined variable x"}},
+		{`import("a/b"); b.c()`, nil},
+		{"[a, *b] = [1, 2]; {(c): d} = {\"c\": a}; puts(a, b, d)", []string{"0:21: undefined variable c"}},
+		{"h = {}; h.size(); h.key", nil},
+	}
+
+	for _, tt := range tests {
+		_, p := createProgram(tt.input)
+		if fmt.Sprint(p.Errors()) != fmt.Sprint(tt.expected) {
+			t.Errorf("%q: expected errors %v, got %v", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
+func TestResolvingWithGlobals(t *testing.T) {
+	p := New(lexer.New("a = 1"), nil)
+	_, globals := p.ParseProgram()
+
+	Predefined = func(name string) bool { return false }
+	defer func() { Predefined = nil }()
+
+	p = New(lexer.New("b = a"), globals)
+	program, globals := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if fmt.Sprint(globals) != "[a b]" {
+		t.Errorf("expected globals [a b], got %v", globals)
+	}
+
+	assign := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Assign)
+	if value := assign.Value.(*ast.Identifier); value.Slot != 0 {
+		t.Errorf("expected a in slot 0, got %d", value.Slot)
+	}
+}
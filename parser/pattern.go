@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/token"
+)
+
+// checkPattern reports an error unless target can be assigned to. Besides
+// identifiers, index expressions and properties these are array and hash
+// literals of targets, which destructure the assigned value. An array
+// pattern may contain a single splat.
+func (p *Parser) checkPattern(target ast.Expression) bool {
+	switch t := target.(type) {
+	case *ast.Identifier, *ast.Index:
+		return true
+	case *ast.ObjectCall:
+		if isProperty(t) {
+			return true
+		}
+	case *ast.Array:
+		splats := 0
+		for _, element := range t.Elements {
+			if splat, ok := element.(*ast.Splat); ok {
+				splats++
+				element = splat.Value
+			}
+			if !p.checkPattern(element) {
+				return false
+			}
+		}
+		if splats > 1 {
+			p.patternError("only one splat is allowed in %s", t)
+			return false
+		}
+		return true
+	case *ast.Hash:
+		for _, pair := range t.Pairs {
+			if pair.Value == nil {
+				p.patternError("cannot assign to %s", pair.Key)
+				return false
+			}
+			if !p.checkPattern(pair.Value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	p.patternError("cannot assign to %s", target)
+	return false
+}
+
+func (p *Parser) patternError(format string, target ast.Expression) {
+	msg := fmt.Sprintf("%d:%d: "+format, p.curToken.LineNumber, p.curToken.LinePosition, target)
+	p.errors = append(p.errors, msg)
+}
+
+// parseMultipleAssign parses `a, b = 1, 2` after the first target was
+// parsed. The targets become an array pattern, several values an array.
+func (p *Parser) parseMultipleAssign(first ast.Expression) ast.Expression {
+	targets := &ast.Array{Token: p.curToken, Elements: []ast.Expression{first}}
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		targets.Elements = append(targets.Elements, p.parseExpression(ASSIGN))
+	}
+
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+	if !p.checkPattern(targets) {
+		return nil
+	}
+
+	assign := &ast.Assign{Token: p.curToken, Operator: p.curToken.Literal, Name: targets}
+	p.nextToken()
+	assign.Value = p.parseValueList()
+
+	return assign
+}
+
+// parseValueList parses one or more comma separated expressions. More
+// than one expression are combined into an array.
+func (p *Parser) parseValueList() ast.Expression {
+	first := p.curToken
+	value := p.parseExpression(LOWEST)
+	if !p.peekTokenIs(token.COMMA) {
+		return value
+	}
+
+	values := &ast.Array{Token: first, Elements: []ast.Expression{value}}
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		values.Elements = append(values.Elements, p.parseExpression(LOWEST))
+	}
+	return values
+}
@@ -1,13 +1,15 @@
 package parser
 
 import (
-	"path/filepath"
-
 	"github.com/flipez/rocket-lang/ast"
 	"github.com/flipez/rocket-lang/token"
 )
 
-func (p *Parser) ParseProgram() (*ast.Program, map[string]struct{}) {
+// ParseProgram parses the program and resolves its variables. The variables
+// of the program start with the globals given to New, like the ones of the
+// earlier lines of a REPL. They are returned for the parser of the next
+// program.
+func (p *Parser) ParseProgram() (*ast.Program, []string) {
 	program := &ast.Program{}
 	program.Statements = []ast.Statement{}
 
@@ -15,16 +17,16 @@ func (p *Parser) ParseProgram() (*ast.Program, map[string]struct{}) {
 		stmt := p.parseStatement()
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
-
-			if expStmt, ok := stmt.(*ast.ExpressionStatement); ok {
-				if importExpr, ok := expStmt.Expression.(*ast.Import); ok {
-					implicitVarName := filepath.Base(importExpr.Name.String())
-					p.imports[implicitVarName] = struct{}{}
-				}
-			}
 		}
 		p.nextToken()
 	}
 
-	return program, p.imports
+	// the scope copies the globals, the program of an earlier line may
+	// share them
+	globals := newScope(nil, p.globals)
+	p.resolveScope(globals, program)
+	program.Locals = ast.NewLocals(globals.names)
+	p.globals = globals.names
+
+	return program, p.globals
 }
@@ -0,0 +1,407 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/flipez/rocket-lang/ast"
+	"github.com/flipez/rocket-lang/token"
+)
+
+// Predefined reports whether a variable is defined without being assigned
+// in the program, like builtin functions. The evaluator knows these names and
+// imports this package, so it sets Predefined in its init function. In a
+// program which doesn't link the evaluator, e.g. the parser tests, it is nil
+// and undefined variables are not reported.
+var Predefined func(name string) bool
+
+// scope holds the variables of the program, a function, a loop or a
+// comprehension clause. Every scope becomes an environment at runtime,
+// which stores the values in slots in the order of names.
+type scope struct {
+	outer *scope
+	names []string
+	slots map[string]int
+
+	// bound holds the position every name is bound at first, names of
+	// earlier programs like the lines of a REPL are bound before all
+	bound []position
+
+	// function is set for the scope of a function body defined at
+	// definition. Its dynamic names are variables of outer scopes which
+	// are only bound after the definition.
+	function   bool
+	definition position
+	dynamic    map[string]bool
+}
+
+// position is the line and column of a token.
+type position struct {
+	line, column int
+}
+
+func positionOf(tok token.Token) position {
+	return position{tok.LineNumber, tok.LinePosition}
+}
+
+func (p position) before(other position) bool {
+	return p.line < other.line || p.line == other.line && p.column < other.column
+}
+
+// newScope returns a scope with the variables names, which are bound before
+// the source.
+func newScope(outer *scope, names []string) *scope {
+	s := &scope{outer: outer}
+	for _, name := range names {
+		s.define(name, position{-1, -1})
+	}
+	return s
+}
+
+// newFunctionScope returns the scope of the body of a function defined at
+// tok.
+func newFunctionScope(outer *scope, tok token.Token) *scope {
+	return &scope{outer: outer, function: true, definition: positionOf(tok)}
+}
+
+func (s *scope) slot(name string) int {
+	if slot, ok := s.slots[name]; ok {
+		return slot
+	}
+	return -1
+}
+
+// define adds name bound at pos to the scope, hiding variables of outer
+// scopes.
+func (s *scope) define(name string, pos position) {
+	if s.slot(name) != -1 {
+		return
+	}
+	if s.slots == nil {
+		s.slots = make(map[string]int)
+	}
+	s.slots[name] = len(s.names)
+	s.names = append(s.names, name)
+	s.bound = append(s.bound, pos)
+}
+
+// declare adds a variable which is assigned in the scope at pos, unless it
+// belongs to an outer scope. Assignments change those like at runtime.
+//
+// Inside a function a variable of an outer scope is only changed if it is
+// bound before the function is defined. Whether one which is bound later
+// already exists depends on when the function is called, so the function
+// looks it up by name when it runs: it changes the outer variable if it is
+// set by then and its own one otherwise.
+func (s *scope) declare(name string, pos position) {
+	var function *scope
+	for cur := s; cur != nil; cur = cur.outer {
+		if slot := cur.slot(name); slot != -1 {
+			if function != nil && !cur.bound[slot].before(function.definition) {
+				function.makeDynamic(name)
+			}
+			return
+		}
+		if cur.dynamic[name] {
+			return
+		}
+		if cur.function {
+			function = cur
+		}
+	}
+	s.define(name, pos)
+}
+
+func (s *scope) makeDynamic(name string) {
+	if s.dynamic == nil {
+		s.dynamic = make(map[string]bool)
+	}
+	s.dynamic[name] = true
+}
+
+// lookup sets the depth and slot of the variable of ident and reports
+// whether it is declared. The slot of other names is -1, they are looked
+// up by name. Dynamic names of a function are left unresolved, they are
+// looked up by name like before the resolver existed.
+func (s *scope) lookup(ident *ast.Identifier) bool {
+	ident.Resolved, ident.Slot = true, -1
+
+	depth := 0
+	for cur := s; cur != nil; cur = cur.outer {
+		if slot := cur.slot(ident.Value); slot != -1 {
+			ident.Depth, ident.Slot = depth, slot
+			return true
+		}
+		if cur.dynamic[ident.Value] {
+			ident.Resolved = false
+			return true
+		}
+		depth++
+	}
+	return false
+}
+
+// resolveScope resolves the identifiers of nodes, which are evaluated in
+// s. All variables assigned in s are declared first, so functions can use
+// variables which are assigned after them.
+func (p *Parser) resolveScope(s *scope, nodes ...ast.Node) {
+	for _, node := range nodes {
+		p.resolve(node, s, true)
+	}
+	for _, node := range nodes {
+		p.resolve(node, s, false)
+	}
+}
+
+// resolve walks the nodes evaluated in s. With declaring set it only
+// declares the variables assigned in s, otherwise it resolves identifiers
+// and the nested scopes.
+func (p *Parser) resolve(node ast.Node, s *scope, declaring bool) {
+	switch node := node.(type) {
+	case *ast.Program:
+		for _, statement := range node.Statements {
+			p.resolve(statement, s, declaring)
+		}
+	case *ast.Block:
+		if node == nil {
+			return
+		}
+		for _, statement := range node.Statements {
+			p.resolve(statement, s, declaring)
+		}
+	case *ast.ExpressionStatement:
+		p.resolve(node.Expression, s, declaring)
+	case *ast.Return:
+		p.resolve(node.ReturnValue, s, declaring)
+	case *ast.Yield:
+		p.resolve(node.Value, s, declaring)
+	case *ast.Identifier:
+		if !declaring {
+			p.resolveIdentifier(node, s)
+		}
+	case *ast.Assign:
+		p.resolveTarget(node.Name, s, declaring)
+		p.resolve(node.Value, s, declaring)
+	case *ast.Import:
+		if name, ok := node.Name.(*ast.String); ok && declaring {
+			s.declare(filepath.Base(name.Value), positionOf(node.Token))
+		}
+		p.resolve(node.Name, s, declaring)
+	case *ast.Function:
+		if declaring {
+			if node.Name != "" {
+				s.declare(node.Name, positionOf(node.Token))
+			}
+			return
+		}
+		p.resolveFunction(node, s)
+	case *ast.Foreach:
+		p.resolve(node.Value, s, declaring)
+		if !declaring {
+			p.resolveForeach(node, s)
+		}
+	case *ast.While:
+		if !declaring {
+			node.Locals = p.resolveLoop(s, node.Condition, node.Body)
+		}
+	case *ast.Until:
+		if !declaring {
+			node.Locals = p.resolveLoop(s, node.Condition, node.Body)
+		}
+	case *ast.Loop:
+		if !declaring {
+			node.Locals = p.resolveLoop(s, node.Body)
+		}
+	case *ast.Modifier:
+		switch node.Token.Type {
+		case token.WHILE, token.UNTIL:
+			if !declaring {
+				node.Locals = p.resolveLoop(s, node.Condition, node.Statement)
+			}
+		default:
+			p.resolve(node.Statement, s, declaring)
+			p.resolve(node.Condition, s, declaring)
+		}
+	case *ast.Comprehension:
+		if len(node.Clauses) == 0 {
+			return
+		}
+		p.resolve(node.Clauses[0].Foreach.Value, s, declaring)
+		if !declaring {
+			p.resolveComprehension(node, s)
+		}
+	case *ast.Array:
+		for _, element := range node.Elements {
+			p.resolve(element, s, declaring)
+		}
+	case *ast.Hash:
+		for _, pair := range node.Pairs {
+			p.resolve(pair.Key, s, declaring)
+			p.resolve(pair.Value, s, declaring)
+		}
+	case *ast.Splat:
+		p.resolve(node.Value, s, declaring)
+	case *ast.Prefix:
+		p.resolve(node.Right, s, declaring)
+	case *ast.Infix:
+		p.resolve(node.Left, s, declaring)
+		p.resolve(node.Right, s, declaring)
+	case *ast.If:
+		p.resolve(node.Condition, s, declaring)
+		p.resolve(node.Consequence, s, declaring)
+		p.resolve(node.Alternative, s, declaring)
+	case *ast.Unless:
+		p.resolve(node.Condition, s, declaring)
+		p.resolve(node.Consequence, s, declaring)
+		p.resolve(node.Alternative, s, declaring)
+	case *ast.Ternary:
+		p.resolve(node.Condition, s, declaring)
+		p.resolve(node.Consequence, s, declaring)
+		p.resolve(node.Alternative, s, declaring)
+	case *ast.Call:
+		p.resolve(node.Callable, s, declaring)
+		for _, argument := range node.Arguments {
+			p.resolve(argument, s, declaring)
+		}
+	case *ast.ObjectCall:
+		// the name of the method is no variable
+		p.resolve(node.Object, s, declaring)
+		if call, ok := node.Call.(*ast.Call); ok {
+			for _, argument := range call.Arguments {
+				p.resolve(argument, s, declaring)
+			}
+		}
+	case *ast.Index:
+		p.resolve(node.Left, s, declaring)
+		p.resolve(node.Index, s, declaring)
+	case *ast.RangeIndex:
+		p.resolve(node.Left, s, declaring)
+		p.resolve(node.FirstIndex, s, declaring)
+		p.resolve(node.SecondIndex, s, declaring)
+	}
+}
+
+// resolveTarget walks the target of an assignment. Identifiers in it are
+// declared, hash keys, indexes and objects of properties are evaluated.
+func (p *Parser) resolveTarget(target ast.Expression, s *scope, declaring bool) {
+	switch t := target.(type) {
+	case *ast.Identifier:
+		if declaring {
+			s.declare(t.Value, positionOf(t.Token))
+		} else {
+			s.lookup(t)
+		}
+	case *ast.Splat:
+		p.resolveTarget(t.Value, s, declaring)
+	case *ast.Array:
+		for _, element := range t.Elements {
+			p.resolveTarget(element, s, declaring)
+		}
+	case *ast.Hash:
+		for _, pair := range t.Pairs {
+			p.resolve(pair.Key, s, declaring)
+			p.resolveTarget(pair.Value, s, declaring)
+		}
+	default:
+		p.resolve(target, s, declaring)
+	}
+}
+
+// definePattern defines the variables of a destructuring pattern in s.
+func definePattern(pattern ast.Expression, s *scope) {
+	switch t := pattern.(type) {
+	case *ast.Identifier:
+		s.define(t.Value, positionOf(t.Token))
+	case *ast.Splat:
+		definePattern(t.Value, s)
+	case *ast.Array:
+		for _, element := range t.Elements {
+			definePattern(element, s)
+		}
+	case *ast.Hash:
+		for _, pair := range t.Pairs {
+			definePattern(pair.Value, s)
+		}
+	}
+}
+
+func (p *Parser) resolveIdentifier(ident *ast.Identifier, s *scope) {
+	if s.lookup(ident) || Predefined == nil || Predefined(ident.Value) {
+		return
+	}
+
+	msg := fmt.Sprintf("%d:%d: undefined variable %s", ident.Token.LineNumber, ident.Token.LinePosition, ident.Value)
+	p.errors = append(p.errors, msg)
+}
+
+func (p *Parser) resolveFunction(fn *ast.Function, s *scope) {
+	body := newFunctionScope(s, fn.Token)
+	for _, param := range fn.Parameters {
+		body.define(param.Value, positionOf(param.Token))
+	}
+
+	p.resolveScope(body, fn.Body)
+	fn.Locals = ast.NewLocals(body.names)
+}
+
+// resolveForeach resolves the loop variables and the body of a foreach.
+// Like other assignments the loop variables may belong to an outer scope.
+func (p *Parser) resolveForeach(fe *ast.Foreach, s *scope) {
+	loop := &scope{outer: s}
+	if fe.Index != "" {
+		loop.declare(fe.Index, positionOf(fe.Token))
+	}
+	if fe.Pattern != nil {
+		p.resolveTarget(fe.Pattern, loop, true)
+	} else {
+		loop.declare(fe.Ident, positionOf(fe.Token))
+	}
+
+	p.resolve(fe.Body, loop, true)
+	if fe.Pattern != nil {
+		p.resolveTarget(fe.Pattern, loop, false)
+	}
+	p.resolve(fe.Body, loop, false)
+	fe.Locals = ast.NewLocals(loop.names)
+}
+
+// resolveLoop resolves the condition and body of a while, until or loop,
+// which have their own scope, and returns its variables.
+func (p *Parser) resolveLoop(s *scope, nodes ...ast.Node) *ast.Locals {
+	loop := &scope{outer: s}
+	p.resolveScope(loop, nodes...)
+	return ast.NewLocals(loop.names)
+}
+
+// resolveComprehension resolves the clauses of a comprehension, each one
+// is a scope defining its variables. The value of the first clause is
+// evaluated outside, the value of every other one in the clause before.
+func (p *Parser) resolveComprehension(c *ast.Comprehension, s *scope) {
+	outer := s
+	for i := range c.Clauses {
+		clause := &c.Clauses[i]
+		inner := &scope{outer: outer}
+		if clause.Foreach.Index != "" {
+			inner.define(clause.Foreach.Index, positionOf(clause.Foreach.Token))
+		}
+		if clause.Foreach.Pattern != nil {
+			definePattern(clause.Foreach.Pattern, inner)
+		} else {
+			inner.define(clause.Foreach.Ident, positionOf(clause.Foreach.Token))
+		}
+
+		nodes := []ast.Node{clause.Condition}
+		if clause.Foreach.Pattern != nil {
+			nodes = append(nodes, clause.Foreach.Pattern)
+		}
+		if i+1 < len(c.Clauses) {
+			nodes = append(nodes, c.Clauses[i+1].Foreach.Value)
+		} else {
+			nodes = append(nodes, c.Key, c.Value)
+		}
+		p.resolveScope(inner, nodes...)
+
+		clause.Locals = ast.NewLocals(inner.names)
+		outer = inner
+	}
+}
@@ -10,7 +10,7 @@ func (p *Parser) parseReturn() *ast.Return {
 
 	p.nextToken()
 
-	stmt.ReturnValue = p.parseExpression(LOWEST)
+	stmt.ReturnValue = p.parseValueList()
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
@@ -0,0 +1,13 @@
+package parser
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+)
+
+func (p *Parser) parseSplat() ast.Expression {
+	splat := &ast.Splat{Token: p.curToken}
+	p.nextToken()
+	splat.Value = p.parseExpression(PREFIX)
+
+	return splat
+}
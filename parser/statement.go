@@ -5,11 +5,42 @@ import (
 	"github.com/flipez/rocket-lang/token"
 )
 
+// modifiers are the keywords which can follow a statement on the same line,
+// like in `puts(x) if debug`.
+var modifiers = map[token.TokenType]bool{
+	token.IF:     true,
+	token.UNLESS: true,
+	token.WHILE:  true,
+	token.UNTIL:  true,
+}
+
 func (p *Parser) parseStatement() ast.Statement {
+	var stmt ast.Statement
 	switch p.curToken.Type {
 	case token.RETURN:
-		return p.parseReturn()
+		stmt = p.parseReturn()
+	case token.YIELD:
+		stmt = p.parseYield()
 	default:
-		return p.parseExpressionStatement()
+		stmt = p.parseExpressionStatement()
 	}
+
+	return p.parseModifiers(stmt)
+}
+
+func (p *Parser) parseModifiers(stmt ast.Statement) ast.Statement {
+	for !p.curTokenIs(token.SEMICOLON) && modifiers[p.peekToken.Type] && p.peekToken.LineNumber == p.curToken.EndLineNumber {
+		p.nextToken()
+		modifier := &ast.Modifier{Token: p.curToken, Statement: stmt}
+
+		p.nextToken()
+		modifier.Condition = p.parseExpression(LOWEST)
+
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+		stmt = modifier
+	}
+
+	return stmt
 }
@@ -1,9 +1,132 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/flipez/rocket-lang/ast"
 )
 
+// escapes are the escape sequences of strings in double quotes, triple
+// quotes and heredocs. Other backslashes are kept as they are.
+var escapes = map[byte]string{
+	'n':  "\n",
+	'r':  "\r",
+	't':  "\t",
+	'0':  "\x00",
+	'\\': "\\",
+	'"':  "\"",
+}
+
 func (p *Parser) parseString() ast.Expression {
-	return &ast.String{Token: p.curToken, Value: p.curToken.Literal}
+	str := &ast.String{Token: p.curToken}
+	literal := p.curToken.Literal
+
+	var terminated bool
+	switch {
+	case strings.HasPrefix(literal, "<<~"):
+		str.Delimiter, str.Value, terminated = parseHeredoc(literal)
+	case strings.HasPrefix(literal, `"""`):
+		str.Delimiter = `"""`
+		terminated = len(literal) >= 6 && closedBy(literal, `"""`)
+		if terminated {
+			// a newline after the opening quotes is not part of the string
+			body := strings.TrimPrefix(literal[3:len(literal)-3], "\n")
+			str.Value = unescape(body)
+		}
+	case strings.HasPrefix(literal, "'"):
+		str.Delimiter = "'"
+		terminated = len(literal) >= 2 && strings.HasSuffix(literal, "'")
+		if terminated {
+			str.Value = literal[1 : len(literal)-1]
+		}
+	default:
+		str.Delimiter = `"`
+		terminated = len(literal) >= 2 && closedBy(literal, `"`)
+		if terminated {
+			str.Value = unescape(literal[1 : len(literal)-1])
+		}
+	}
+
+	if !terminated {
+		msg := fmt.Sprintf("%d:%d: string is not terminated, expected %s", p.curToken.LineNumber, p.curToken.LinePosition, closing(str.Delimiter))
+		p.errors = append(p.errors, msg)
+	}
+	return str
+}
+
+// parseHeredoc returns the marker and value of a heredoc and whether its
+// closing line was found. The indentation all lines of the body have in
+// common is removed.
+func parseHeredoc(literal string) (string, string, bool) {
+	lines := strings.Split(literal, "\n")
+	marker, lines := lines[0], lines[1:]
+	name := strings.TrimPrefix(marker, "<<~")
+
+	if len(lines) == 0 || strings.TrimSpace(lines[len(lines)-1]) != name {
+		return marker, "", false
+	}
+	lines = lines[:len(lines)-1]
+
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		width := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || width < indent {
+			indent = width
+		}
+	}
+
+	var value strings.Builder
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) >= indent && indent != -1 {
+			line = line[indent:]
+		} else {
+			line = strings.TrimLeft(line, " \t")
+		}
+		value.WriteString(line)
+		value.WriteString("\n")
+	}
+	return marker, unescape(value.String()), true
+}
+
+// closedBy reports whether literal ends with the delimiter, which is not
+// escaped by a backslash.
+func closedBy(literal, delimiter string) bool {
+	if !strings.HasSuffix(literal, delimiter) {
+		return false
+	}
+	body := literal[:len(literal)-len(delimiter)]
+	backslashes := len(body) - len(strings.TrimRight(body, `\`))
+	return backslashes%2 == 0
+}
+
+// closing returns the delimiter which closes a string opened by delimiter.
+func closing(delimiter string) string {
+	if strings.HasPrefix(delimiter, "<<~") {
+		return "a line with " + strings.TrimPrefix(delimiter, "<<~")
+	}
+	return delimiter
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			if escaped, ok := escapes[s[i+1]]; ok {
+				out.WriteString(escaped)
+				i++
+				continue
+			}
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
 }
@@ -0,0 +1,11 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/flipez/rocket-lang/ast"
+)
+
+func (p *Parser) parseSymbol() ast.Expression {
+	return &ast.Symbol{Token: p.curToken, Value: strings.TrimPrefix(p.curToken.Literal, ":")}
+}
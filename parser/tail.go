@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"github.com/flipez/rocket-lang/ast"
+)
+
+// markTailCalls marks the calls in a function body whose result is returned
+// directly: the values of return statements and the last expression of the
+// body, also inside the branches of conditionals. Nested functions are
+// marked on their own.
+func markTailCalls(node ast.Node, tail bool) {
+	switch node := node.(type) {
+	case *ast.Block:
+		if node == nil {
+			return
+		}
+		for i, statement := range node.Statements {
+			markTailCalls(statement, tail && i == len(node.Statements)-1)
+		}
+	case *ast.ExpressionStatement:
+		markTailCalls(node.Expression, tail)
+	case *ast.Return:
+		markTailCalls(node.ReturnValue, true)
+	case *ast.Modifier:
+		markTailCalls(node.Statement, false)
+	case *ast.Call:
+		node.Tail = tail
+	case *ast.If:
+		markTailCalls(node.Consequence, tail)
+		markTailCalls(node.Alternative, tail)
+	case *ast.Unless:
+		markTailCalls(node.Consequence, tail)
+		markTailCalls(node.Alternative, tail)
+	case *ast.Ternary:
+		markTailCalls(node.Consequence, tail)
+		markTailCalls(node.Alternative, tail)
+	case *ast.While:
+		markTailCalls(node.Body, false)
+	case *ast.Until:
+		markTailCalls(node.Body, false)
+	case *ast.Loop:
+		markTailCalls(node.Body, false)
+	case *ast.Foreach:
+		markTailCalls(node.Body, false)
+	}
+}
@@ -12,6 +12,7 @@ func (p *Parser) parseTernary(left ast.Expression) ast.Expression {
 
 	expression.Consequence = p.parseExpression(p.curPrecedence())
 
+	p.splitPeekSymbol()
 	if p.peekTokenIs(token.COLON) {
 		p.nextToken()
 		p.nextToken()
@@ -2,31 +2,33 @@ package parser
 
 import (
 	"github.com/flipez/rocket-lang/ast"
-	"github.com/flipez/rocket-lang/token"
 )
 
 func (p *Parser) parseWhile() ast.Expression {
 	expression := &ast.While{Token: p.curToken}
 
-	if !p.expectPeek(token.LPAREN) {
+	expression.Condition, expression.Body = p.parseConditionalBlock()
+	if expression.Body == nil {
 		return nil
 	}
 
-	p.nextToken()
-	expression.Condition = p.parseExpression(LOWEST)
+	return expression
+}
 
-	if !p.expectPeek(token.RPAREN) {
+func (p *Parser) parseUntil() ast.Expression {
+	expression := &ast.Until{Token: p.curToken}
+
+	expression.Condition, expression.Body = p.parseConditionalBlock()
+	if expression.Body == nil {
 		return nil
 	}
 
-	if p.peekTokenIs(token.LBRACE) {
-		p.nextToken()
-	}
-	expression.Body = p.parseBlock()
+	return expression
+}
 
-	if p.curTokenIs(token.RBRACE) {
-		p.nextToken()
-	}
+func (p *Parser) parseLoop() ast.Expression {
+	expression := &ast.Loop{Token: p.curToken}
+	expression.Body = p.parseBody()
 
 	return expression
 }
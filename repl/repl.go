@@ -26,17 +26,17 @@ func Start(in io.Reader, out io.Writer) {
 	shell.SetPrompt("🚀 > ")
 
 	env := object.NewEnvironment()
-	imports := make(map[string]struct{})
+	var globals []string
 
 	shell.Println(SplashScreen())
 	shell.NotFound(func(ctx *ishell.Context) {
 
 		l := lexer.New(strings.Join(ctx.RawArgs, " "))
-		p := parser.New(l, imports)
+		p := parser.New(l, globals)
 
 		var program *ast.Program
 
-		program, imports = p.ParseProgram()
+		program, globals = p.ParseProgram()
 		if len(p.Errors()) > 0 {
 			printParserErrors(ctx, p.Errors())
 			return
@@ -0,0 +1,97 @@
+package stdlib
+
+import (
+	"github.com/flipez/rocket-lang/object"
+)
+
+var collectionsFunctions = map[string]object.BuiltinFunction{
+	"heap":         collectionsHeap,
+	"deque":        collectionsDeque,
+	"counter":      collectionsCounter,
+	"default_hash": collectionsDefaultHash,
+}
+
+// collectionsHeap returns a min heap, or a max heap if the first argument
+// is :max or "max". An optional function computes the priority of values.
+func collectionsHeap(args ...object.Object) object.Object {
+	if len(args) > 2 {
+		return object.NewErrorFormat("wrong number of arguments to `collections.heap`. got=%d, want=0..2", len(args))
+	}
+
+	var max bool
+	if len(args) > 0 && !object.IsCallable(args[0]) {
+		switch object.Display(args[0]) {
+		case "min":
+		case "max":
+			max = true
+		default:
+			return object.NewErrorFormat("unknown heap order %s, want=:min|:max", args[0].Inspect())
+		}
+		args = args[1:]
+	}
+
+	var key object.Object
+	if len(args) > 0 {
+		if len(args) > 1 {
+			return object.NewErrorFormat("wrong number of arguments to `collections.heap`. got=%d, want=0..2", len(args))
+		}
+		if !object.IsCallable(args[0]) {
+			return object.NewErrorFormat("key of `collections.heap` must be FUNCTION|BUILTIN, got=%s", args[0].Type())
+		}
+		key = args[0]
+	}
+
+	return object.NewHeap(max, key)
+}
+
+func collectionsDeque(args ...object.Object) object.Object {
+	values, err := optionalValues("collections.deque", args)
+	if err != nil {
+		return err
+	}
+	return object.NewDeque(values)
+}
+
+func collectionsCounter(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return object.NewErrorFormat("wrong number of arguments to `collections.counter`. got=%d, want=0..1", len(args))
+	}
+
+	counter := object.NewCounter()
+	if len(args) == 0 {
+		return counter
+	}
+
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return object.NewErrorFormat("argument 1 to `collections.counter` must be iterable, got=%s", args[0].Type())
+	}
+	if err := counter.Update(iterable); err != nil {
+		return err
+	}
+	return counter
+}
+
+func collectionsDefaultHash(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return object.NewErrorFormat("wrong number of arguments to `collections.default_hash`. got=%d, want=1", len(args))
+	}
+	return object.NewDefaultHash(args[0])
+}
+
+// optionalValues returns the values of the optional iterable argument of
+// the builtin function name.
+func optionalValues(name string, args []object.Object) ([]object.Object, object.Object) {
+	if len(args) > 1 {
+		return nil, object.NewErrorFormat("wrong number of arguments to `%s`. got=%d, want=0..1", name, len(args))
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return nil, object.NewErrorFormat("argument 1 to `%s` must be iterable, got=%s", name, args[0].Type())
+	}
+	return object.Collect(iterable)
+}
@@ -0,0 +1,223 @@
+package stdlib
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+var fsFunctions = map[string]object.BuiltinFunction{
+	"list_dir": fsListDir,
+	"glob":     fsGlob,
+	"walk":     fsWalk,
+	"mkdir_p":  fsMkdirP,
+	"remove":   fsRemove,
+	"rename":   fsRename,
+	"copy":     fsCopy,
+	"stat":     fsStat,
+	"exists?":  fsExists,
+	"tempdir":  fsTempdir,
+	"tempfile": fsTempfile,
+}
+
+func fsListDir(args ...object.Object) object.Object {
+	if err := checkArgs("fs.list_dir", args, stringArg); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(args[0].(*object.String).Value)
+	if err != nil {
+		return object.NewError(err)
+	}
+
+	names := make([]object.Object, len(entries))
+	for i, entry := range entries {
+		names[i] = object.NewString(entry.Name())
+	}
+	return object.NewArray(names)
+}
+
+func fsGlob(args ...object.Object) object.Object {
+	if err := checkArgs("fs.glob", args, stringArg); err != nil {
+		return err
+	}
+
+	matches, err := filepath.Glob(args[0].(*object.String).Value)
+	if err != nil {
+		return object.NewError(err)
+	}
+
+	return object.NewStringArray(matches)
+}
+
+// errStopWalk is returned from the walk function to abort fs.walk once the
+// callback returned an error; the error itself is kept in result.
+var errStopWalk = errors.New("stop walk")
+
+// fsWalk calls the given function with the path of every file and directory
+// below root, including root itself. Walking stops at the first error.
+func fsWalk(args ...object.Object) object.Object {
+	if err := checkArgs("fs.walk", args, stringArg, callableArg); err != nil {
+		return err
+	}
+
+	var result object.Object
+	err := filepath.WalkDir(args[0].(*object.String).Value, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ret := object.ApplyFunction(args[1], []object.Object{object.NewString(path)})
+		if object.IsError(ret) {
+			result = ret
+			return errStopWalk
+		}
+		return nil
+	})
+
+	if err == errStopWalk {
+		return result
+	}
+	if err != nil {
+		return object.NewError(err)
+	}
+	return object.NULL
+}
+
+func fsMkdirP(args ...object.Object) object.Object {
+	if err := checkArgs("fs.mkdir_p", args, stringArg); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(args[0].(*object.String).Value, 0755); err != nil {
+		return object.NewError(err)
+	}
+	return object.TRUE
+}
+
+func fsRemove(args ...object.Object) object.Object {
+	if err := checkArgs("fs.remove", args, stringArg); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(args[0].(*object.String).Value); err != nil {
+		return object.NewError(err)
+	}
+	return object.TRUE
+}
+
+func fsRename(args ...object.Object) object.Object {
+	if err := checkArgs("fs.rename", args, stringArg, stringArg); err != nil {
+		return err
+	}
+
+	if err := os.Rename(args[0].(*object.String).Value, args[1].(*object.String).Value); err != nil {
+		return object.NewError(err)
+	}
+	return object.TRUE
+}
+
+func fsCopy(args ...object.Object) object.Object {
+	if err := checkArgs("fs.copy", args, stringArg, stringArg); err != nil {
+		return err
+	}
+
+	src, err := os.Open(args[0].(*object.String).Value)
+	if err != nil {
+		return object.NewError(err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return object.NewError(err)
+	}
+	if info.IsDir() {
+		return object.NewErrorFormat("fs.copy: %s is a directory", info.Name())
+	}
+
+	dst, err := os.OpenFile(args[1].(*object.String).Value, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return object.NewError(err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return object.NewError(err)
+	}
+	return object.TRUE
+}
+
+func fsStat(args ...object.Object) object.Object {
+	if err := checkArgs("fs.stat", args, stringArg); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(args[0].(*object.String).Value)
+	if err != nil {
+		return object.NewError(err)
+	}
+
+	return newHash(map[string]object.Object{
+		"name":  object.NewString(info.Name()),
+		"size":  object.NewInteger(info.Size()),
+		"mtime": object.NewInteger(info.ModTime().Unix()),
+		"mode":  object.NewInteger(int64(info.Mode().Perm())),
+		"dir?":  nativeBool(info.IsDir()),
+	})
+}
+
+func fsExists(args ...object.Object) object.Object {
+	if err := checkArgs("fs.exists?", args, stringArg); err != nil {
+		return err
+	}
+
+	_, err := os.Stat(args[0].(*object.String).Value)
+	return nativeBool(err == nil)
+}
+
+func fsTempdir(args ...object.Object) object.Object {
+	if err := checkArgs("fs.tempdir", args); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "rocket-lang")
+	if err != nil {
+		return object.NewError(err)
+	}
+	return object.NewString(dir)
+}
+
+func fsTempfile(args ...object.Object) object.Object {
+	if err := checkArgs("fs.tempfile", args); err != nil {
+		return err
+	}
+
+	handle, err := os.CreateTemp("", "rocket-lang")
+	if err != nil {
+		return object.NewError(err)
+	}
+
+	file := object.NewFile(handle.Name())
+	file.Handle = handle
+	return file
+}
+
+func newHash(values map[string]object.Object) *object.Hash {
+	hash := object.NewHash(nil)
+	for k, v := range values {
+		hash.Set(object.NewString(k), v)
+	}
+	return hash
+}
+
+func nativeBool(b bool) *object.Boolean {
+	if b {
+		return object.TRUE
+	}
+	return object.FALSE
+}
@@ -0,0 +1,35 @@
+package stdlib
+
+import (
+	"github.com/flipez/rocket-lang/object"
+)
+
+// partialFunction binds the leading arguments of a callable and returns a
+// function taking the remaining ones.
+func partialFunction(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return object.NewErrorFormat("wrong number of arguments to `partial`. got=%d, want at least 1", len(args))
+	}
+	if !object.IsCallable(args[0]) {
+		return object.NewErrorFormat("argument 1 to `partial` must be FUNCTION|BUILTIN, got=%s", args[0].Type())
+	}
+
+	return object.NewPartialFunction(args[0], args[1:])
+}
+
+// curryFunction turns a callable into a chain of functions taking one
+// argument each. The arity defaults to the amount of parameters of a
+// function and is required for builtins.
+func curryFunction(args ...object.Object) object.Object {
+	if len(args) == 2 {
+		if err := checkArgs("curry", args, callableArg, []object.ObjectType{object.INTEGER_OBJ}); err != nil {
+			return err
+		}
+		return object.NewCurriedFunction(args[0], int(args[1].(*object.Integer).Value))
+	}
+
+	if err := checkArgs("curry", args, []object.ObjectType{object.FUNCTION_OBJ}); err != nil {
+		return err
+	}
+	return object.NewCurriedFunction(args[0], len(args[0].(*object.Function).Parameters))
+}
@@ -5,6 +5,16 @@ import (
 )
 
 func openFunction(args ...object.Object) object.Object {
+	// a trailing block gets the file and the file is closed afterwards
+	var block object.Object
+	if len(args) > 1 {
+		switch args[len(args)-1].(type) {
+		case *object.Function, *object.Builtin:
+			block = args[len(args)-1]
+			args = args[:len(args)-1]
+		}
+	}
+
 	path := ""
 	mode := "r"
 	perm := "0644"
@@ -43,5 +53,12 @@ func openFunction(args ...object.Object) object.Object {
 	if err != nil {
 		return object.NewErrorFormat(err.Error())
 	}
+
+	if block != nil {
+		result := object.ApplyFunction(block, []object.Object{file})
+		file.Handle.Close()
+		file.Position = -1
+		return result
+	}
 	return (file)
 }
@@ -0,0 +1,61 @@
+package stdlib
+
+import (
+	"path/filepath"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+var pathFunctions = map[string]object.BuiltinFunction{
+	"join":     pathJoin,
+	"dirname":  pathDirname,
+	"basename": pathBasename,
+	"ext":      pathExt,
+	"abs":      pathAbs,
+}
+
+func pathJoin(args ...object.Object) object.Object {
+	elements := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.(*object.String)
+		if !ok {
+			return object.NewErrorFormat("argument %d to `path.join` must be STRING, got=%s", i+1, arg.Type())
+		}
+		elements[i] = str.Value
+	}
+
+	return object.NewString(filepath.Join(elements...))
+}
+
+func pathDirname(args ...object.Object) object.Object {
+	if err := checkArgs("path.dirname", args, stringArg); err != nil {
+		return err
+	}
+	return object.NewString(filepath.Dir(args[0].(*object.String).Value))
+}
+
+func pathBasename(args ...object.Object) object.Object {
+	if err := checkArgs("path.basename", args, stringArg); err != nil {
+		return err
+	}
+	return object.NewString(filepath.Base(args[0].(*object.String).Value))
+}
+
+func pathExt(args ...object.Object) object.Object {
+	if err := checkArgs("path.ext", args, stringArg); err != nil {
+		return err
+	}
+	return object.NewString(filepath.Ext(args[0].(*object.String).Value))
+}
+
+func pathAbs(args ...object.Object) object.Object {
+	if err := checkArgs("path.abs", args, stringArg); err != nil {
+		return err
+	}
+
+	abs, err := filepath.Abs(args[0].(*object.String).Value)
+	if err != nil {
+		return object.NewError(err)
+	}
+	return object.NewString(abs)
+}
@@ -0,0 +1,218 @@
+package stdlib
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/flipez/rocket-lang/object"
+)
+
+var (
+	commandArg = []object.ObjectType{object.ARRAY_OBJ}
+	optionsArg = []object.ObjectType{object.HASH_OBJ}
+)
+
+// execFunction runs a command to completion and returns its captured output
+// and exit code. A non-zero exit code is not an error.
+func execFunction(args ...object.Object) object.Object {
+	cmd, stdin, err := newCommand("exec", args)
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	exitCode, err := runCommand(cmd)
+	if err != nil {
+		return err
+	}
+
+	return newHash(map[string]object.Object{
+		"stdout":    object.NewString(stdout.String()),
+		"stderr":    object.NewString(stderr.String()),
+		"exit_code": exitCode,
+	})
+}
+
+// systemFunction runs a command attached to the streams of rocket-lang itself
+// and returns its exit code.
+func systemFunction(args ...object.Object) object.Object {
+	cmd, stdin, err := newCommand("system", args)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdin = os.Stdin
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	exitCode, err := runCommand(cmd)
+	if err != nil {
+		return err
+	}
+	return exitCode
+}
+
+// spawnFunction starts a command without waiting for it and returns a
+// PROCESS whose streams can be read and written while it runs.
+func spawnFunction(args ...object.Object) object.Object {
+	cmd, stdin, err := newCommand("spawn", args)
+	if err != nil {
+		return err
+	}
+
+	var stdinFile *object.File
+	var childEnds []*os.File
+
+	if stdin != nil {
+		cmd.Stdin = stdin
+	} else {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return object.NewError(err)
+		}
+		cmd.Stdin = r
+		childEnds = append(childEnds, r)
+		stdinFile = newPipeFile("stdin", w)
+	}
+
+	stdoutR, stdoutW, pipeErr := os.Pipe()
+	if pipeErr != nil {
+		return object.NewError(pipeErr)
+	}
+	stderrR, stderrW, pipeErr := os.Pipe()
+	if pipeErr != nil {
+		return object.NewError(pipeErr)
+	}
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderrW
+	childEnds = append(childEnds, stdoutW, stderrW)
+
+	startErr := cmd.Start()
+
+	// the child has its own copies now, keeping ours open would prevent
+	// readers from ever seeing EOF
+	for _, f := range childEnds {
+		f.Close()
+	}
+
+	if startErr != nil {
+		stdoutR.Close()
+		stderrR.Close()
+		if stdinFile != nil {
+			stdinFile.Handle.Close()
+		}
+		return object.NewError(startErr)
+	}
+
+	return object.NewProcess(cmd, stdinFile, newPipeFile("stdout", stdoutR), newPipeFile("stderr", stderrR))
+}
+
+// newCommand builds a command from a command array and an optional hash of
+// options. Supported options are `stdin` (STRING), `cwd` (STRING) and `env`
+// (HASH), the latter is added on top of ENV.
+func newCommand(name string, args []object.Object) (*exec.Cmd, *strings.Reader, object.Object) {
+	types := [][]object.ObjectType{commandArg}
+	if len(args) > 1 {
+		types = append(types, optionsArg)
+	}
+	if err := checkArgs(name, args, types...); err != nil {
+		return nil, nil, err
+	}
+
+	command, err := toStrings(name, args[0].(*object.Array).Elements)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(command) == 0 {
+		return nil, nil, object.NewErrorFormat("command given to `%s` must not be empty", name)
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = environ(envHash)
+
+	if len(args) == 1 {
+		return cmd, nil, nil
+	}
+
+	var stdin *strings.Reader
+	for _, pair := range args[1].(*object.Hash).Pairs() {
+		key, ok := pair.Key.(*object.String)
+		if !ok {
+			return nil, nil, object.NewErrorFormat("option keys of `%s` must be STRING, got=%s", name, pair.Key.Type())
+		}
+
+		switch key.Value {
+		case "stdin":
+			value, ok := pair.Value.(*object.String)
+			if !ok {
+				return nil, nil, object.NewErrorFormat("option stdin of `%s` must be STRING, got=%s", name, pair.Value.Type())
+			}
+			stdin = strings.NewReader(value.Value)
+		case "cwd":
+			value, ok := pair.Value.(*object.String)
+			if !ok {
+				return nil, nil, object.NewErrorFormat("option cwd of `%s` must be STRING, got=%s", name, pair.Value.Type())
+			}
+			cmd.Dir = value.Value
+		case "env":
+			value, ok := pair.Value.(*object.Hash)
+			if !ok {
+				return nil, nil, object.NewErrorFormat("option env of `%s` must be HASH, got=%s", name, pair.Value.Type())
+			}
+			cmd.Env = append(cmd.Env, environ(value)...)
+		default:
+			return nil, nil, object.NewErrorFormat("unknown option `%s` for `%s`", key.Value, name)
+		}
+	}
+
+	return cmd, stdin, nil
+}
+
+func runCommand(cmd *exec.Cmd) (*object.Integer, object.Object) {
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, object.NewError(err)
+	}
+	return object.NewInteger(int64(cmd.ProcessState.ExitCode())), nil
+}
+
+func toStrings(name string, elements []object.Object) ([]string, object.Object) {
+	result := make([]string, len(elements))
+	for i, element := range elements {
+		str, ok := element.(*object.String)
+		if !ok {
+			return nil, object.NewErrorFormat("command given to `%s` must only contain STRING, got=%s", name, element.Type())
+		}
+		result[i] = str.Value
+	}
+	return result, nil
+}
+
+// environ converts a hash into the KEY=value form used by os/exec.
+func environ(hash *object.Hash) []string {
+	result := make([]string, 0, hash.Len())
+	for _, pair := range hash.Pairs() {
+		result = append(result, object.Display(pair.Key)+"="+object.Display(pair.Value))
+	}
+	return result
+}
+
+func newPipeFile(name string, handle *os.File) *object.File {
+	file := object.NewFile("!" + strings.ToUpper(name) + "_PIPE!")
+	file.Handle = handle
+	return file
+}
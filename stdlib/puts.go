@@ -6,10 +6,39 @@ import (
 	"github.com/flipez/rocket-lang/object"
 )
 
+// LegacyOutput makes puts and print use the debug representation of objects
+// again, which prints strings in quotes. It exists to migrate old scripts and
+// their expected output.
+var LegacyOutput bool
+
 func putsFunction(args ...object.Object) object.Object {
 	for _, arg := range args {
-		fmt.Println(arg.Inspect())
+		fmt.Println(output(arg))
+	}
+
+	return nil
+}
+
+func printFunction(args ...object.Object) object.Object {
+	for _, arg := range args {
+		fmt.Print(output(arg))
+	}
+
+	return nil
+}
+
+// ppFunction pretty prints the inspect representation of every argument.
+func ppFunction(args ...object.Object) object.Object {
+	for _, arg := range args {
+		fmt.Println(object.Pretty(arg, object.DefaultPrettyWidth))
 	}
 
 	return nil
 }
+
+func output(o object.Object) string {
+	if LegacyOutput {
+		return o.Inspect()
+	}
+	return object.Display(o)
+}
@@ -0,0 +1,20 @@
+package stdlib
+
+import (
+	"github.com/flipez/rocket-lang/object"
+)
+
+func regexFunction(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return object.NewErrorFormat("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return object.NewErrorFormat("argument to `regex` must be STRING, got=%s", args[0].Type())
+	}
+
+	re, err := object.NewRegex(args[0].(*object.String).Value)
+	if err != nil {
+		return object.NewError(err)
+	}
+	return re
+}
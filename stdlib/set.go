@@ -0,0 +1,24 @@
+package stdlib
+
+import (
+	"github.com/flipez/rocket-lang/object"
+)
+
+// setFunction returns a set of the values of the optional iterable, like
+// set([1, 2]) or set("abc").
+func setFunction(args ...object.Object) object.Object {
+	values, err := optionalValues("set", args)
+	if err != nil {
+		return err
+	}
+
+	s := object.NewSet(nil)
+	for _, value := range values {
+		element, ok := value.(object.Hashable)
+		if !ok {
+			return object.NewErrorFormat("unusable as set element: %s", value.Type())
+		}
+		s.Add(element)
+	}
+	return s
+}
@@ -1,18 +1,101 @@
 package stdlib
 
 import (
+	"os"
+	"strings"
+
 	"github.com/flipez/rocket-lang/object"
 )
 
 var Builtins = map[string]*object.Builtin{}
 
+// Modules contains all builtin modules like `fs` or `path`. Their functions
+// are called like methods, e.g. `fs.exists?("file")`.
+var Modules = map[string]*object.Module{}
+
+// Globals contains the variables every program starts with, like `ARGV` and
+// `ENV`.
+var Globals = map[string]object.Object{}
+
+// envHash backs `ENV`. Changes to it are passed on to started processes.
+var envHash = object.NewHash(nil)
+
 func init() {
 	RegisterFunction("puts", putsFunction)
+	RegisterFunction("print", printFunction)
+	RegisterFunction("pp", ppFunction)
 	RegisterFunction("exit", exitFunction)
 	RegisterFunction("raise", raiseFunction)
 	RegisterFunction("open", openFunction)
+	RegisterFunction("regex", regexFunction)
+	RegisterFunction("exec", execFunction)
+	RegisterFunction("system", systemFunction)
+	RegisterFunction("spawn", spawnFunction)
+	RegisterFunction("partial", partialFunction)
+	RegisterFunction("curry", curryFunction)
+	RegisterFunction("set", setFunction)
+
+	RegisterModule("fs", fsFunctions)
+	RegisterModule("path", pathFunctions)
+	RegisterModule("collections", collectionsFunctions)
+
+	for _, variable := range os.Environ() {
+		parts := strings.SplitN(variable, "=", 2)
+		envHash.Set(object.NewString(parts[0]), object.NewString(parts[1]))
+	}
+	Globals["ENV"] = envHash
+	SetArguments(nil)
+}
+
+// SetArguments sets `ARGV` to the given command line arguments.
+func SetArguments(args []string) {
+	Globals["ARGV"] = object.NewStringArray(args)
 }
 
 func RegisterFunction(name string, function object.BuiltinFunction) {
 	Builtins[name] = object.NewBuiltin(name, function)
 }
+
+func RegisterModule(name string, functions map[string]object.BuiltinFunction) {
+	attributes := object.NewHash(nil)
+	for fnName, function := range functions {
+		attributes.Set(object.NewString(fnName), object.NewBuiltin(name+"."+fnName, function))
+	}
+
+	Modules[name] = object.NewModule(name, attributes)
+}
+
+// checkArgs validates the amount and types of the arguments given to the
+// builtin function name. Every entry of types lists the allowed types of
+// the argument on that position.
+func checkArgs(name string, args []object.Object, types ...[]object.ObjectType) object.Object {
+	if len(args) != len(types) {
+		return object.NewErrorFormat("wrong number of arguments to `%s`. got=%d, want=%d", name, len(args), len(types))
+	}
+
+	for idx, allowed := range types {
+		if !isOneOf(args[idx].Type(), allowed) {
+			names := make([]string, len(allowed))
+			for i, t := range allowed {
+				names[i] = string(t)
+			}
+			return object.NewErrorFormat("argument %d to `%s` must be %s, got=%s", idx+1, name, strings.Join(names, "|"), args[idx].Type())
+		}
+	}
+
+	return nil
+}
+
+func isOneOf(t object.ObjectType, types []object.ObjectType) bool {
+	for _, allowed := range types {
+		if t == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	stringArg   = []object.ObjectType{object.STRING_OBJ}
+	callableArg = []object.ObjectType{object.FUNCTION_OBJ, object.BUILTIN_OBJ}
+)
@@ -6,11 +6,17 @@ import (
 
 type TokenType string
 
+// Token is a part of the source of a program. Lines start at 0 and columns
+// at 1, they count characters. The position is the one of the first
+// character, the end position the one after the last character.
 type Token struct {
 	Type         TokenType
 	Literal      string
 	LineNumber   int
 	LinePosition int
+
+	EndLineNumber   int
+	EndLinePosition int
 }
 
 const (
@@ -21,6 +27,7 @@ const (
 	INT    = "INT"   // 123456
 	FLOAT  = "FLOAT" // 123.456
 	STRING = "STRING"
+	SYMBOL = "SYMBOL" // :name
 
 	ASSIGN   = "="
 	PLUS     = "+"
@@ -29,8 +36,28 @@ const (
 	ASTERISK = "*"
 	SLASH    = "/"
 	PERCENT  = "%"
+	POWER    = "**"
+
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+	PERCENT_ASSIGN  = "%="
+
+	AND = "&&"
+	OR  = "||"
+
+	BIT_AND     = "&"
+	BIT_OR      = "|"
+	BIT_XOR     = "^"
+	SHIFT_LEFT  = "<<"
+	SHIFT_RIGHT = ">>"
+
+	PIPE       = "|>"
+	METHOD_REF = "&:"
 
-	QUESTION = "?"
+	QUESTION      = "?"
+	NULL_COALESCE = "??"
 
 	LT    = "<"
 	LT_EQ = "<="
@@ -52,20 +79,30 @@ const (
 	FUNCTION = "FUNCTION"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
+	NULL     = "NULL"
 	IF       = "IF"
+	ELSIF    = "ELSIF"
+	UNLESS   = "UNLESS"
 	ELSE     = "ELSE"
 	END      = "END"
 	RETURN   = "RETURN"
+	YIELD    = "YIELD"
 
 	EQ     = "=="
 	NOT_EQ = "!="
+	MATCH  = "=~"
 
-	PERIOD = "."
+	PERIOD      = "."
+	SAFE_PERIOD = "&."
+	ARROW       = "->"
 
 	FOREACH = "FOREACH"
 	IN      = "IN"
 
 	WHILE = "WHILE"
+	UNTIL = "UNTIL"
+	LOOP  = "LOOP"
+	DO    = "DO"
 
 	EXPORT = "EXPORT"
 	IMPORT = "IMPORT"
@@ -75,13 +112,21 @@ var keywords = map[string]TokenType{
 	"def":     FUNCTION,
 	"true":    TRUE,
 	"false":   FALSE,
+	"null":    NULL,
+	"nil":     NULL,
 	"if":      IF,
+	"elsif":   ELSIF,
+	"unless":  UNLESS,
 	"end":     END,
 	"else":    ELSE,
 	"return":  RETURN,
+	"yield":   YIELD,
 	"foreach": FOREACH,
 	"in":      IN,
 	"while":   WHILE,
+	"until":   UNTIL,
+	"loop":    LOOP,
+	"do":      DO,
 	"export":  EXPORT,
 	"import":  IMPORT,
 }